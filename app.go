@@ -1,7 +1,10 @@
 package main
 
 import (
+	"canvas-desktop/canvas"
 	"context"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
@@ -19,3 +22,24 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 }
+
+// SelectCSVFile opens a file dialog and returns the chosen path, or "" when cancelled
+func (a *App) SelectCSVFile(title string) (string, error) {
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: title,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV files (*.csv)", Pattern: "*.csv"},
+		},
+	})
+}
+
+// ProgressEvent carries canvas.TaskProgress to the frontend.
+const ProgressEvent = "progress"
+
+func (a *App) emitProgress(progress *canvas.TaskProgress) {
+	if a.ctx == nil {
+		return
+	}
+
+	runtime.EventsEmit(a.ctx, ProgressEvent, progress)
+}
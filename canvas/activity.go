@@ -0,0 +1,123 @@
+package canvas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// InactivityCriteria lists a student when either threshold is hit, zero turns a threshold off.
+type InactivityCriteria struct {
+	InactiveDays       int `json:"inactive_days"`
+	MinActivityMinutes int `json:"min_activity_minutes"`
+}
+
+type InactiveStudent struct {
+	Qualification  string  `json:"qualification" csv:"Qualification"`
+	CourseName     string  `json:"course_name" csv:"Course"`
+	Term           string  `json:"term" csv:"Term"`
+	Section        string  `json:"section" csv:"Section"`
+	Teachers       string  `json:"teachers" csv:"Teachers"`
+	StudentID      string  `json:"student_id" csv:"Student ID"`
+	StudentName    string  `json:"student_name" csv:"Student Name"`
+	LastActivityAt string  `json:"last_activity_at" csv:"Last Activity"`
+	DaysInactive   string  `json:"days_inactive" csv:"Days Inactive"`
+	ActivityTime   string  `json:"activity_time" csv:"Time in Course"`
+	CurrentScore   float32 `json:"current_score" csv:"Current Score"`
+	Reason         string  `json:"reason" csv:"Reason"`
+}
+
+// GetInactiveStudentsByAccountID lists the active students of every course in the account who
+// meet the criteria, sorted by course and section for follow-up by their teachers.
+// opts of nil includes courses with student enrollments
+func (c *Controller) GetInactiveStudentsByAccountID(accountID int, opts *CourseQueryOptions, criteria InactivityCriteria) ([]*InactiveStudent, error) {
+	students := []*InactiveStudent{}
+	if opts == nil {
+		opts = DefaultCourseQueryOptions()
+	}
+
+	courses, err := c.APIClient.GetCoursesByAccountID(accountID, opts)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving courses")
+	}
+
+	now := time.Now()
+	for _, course := range courses {
+		enrollments, err := c.APIClient.GetEnrollmentsByCourseID(course.ID, StudentEnrollment)
+		if err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("error retrieving enrollments of course ID: %d", course.ID))
+		}
+
+		sections, err := c.APIClient.getSectionDirectory(course.ID)
+		if err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("error retrieving sections of course ID: %d", course.ID))
+		}
+
+		_students := []*InactiveStudent{}
+		for _, enrollment := range enrollments {
+			if enrollment.EnrollmentState != "active" {
+				continue
+			}
+
+			reasons := inactivityReasons(enrollment, criteria, now)
+			if len(reasons) == 0 {
+				continue
+			}
+
+			student := &InactiveStudent{
+				Qualification:  course.Account.Name,
+				CourseName:     course.Name,
+				Term:           course.TermName(),
+				StudentID:      enrollment.User.SISUserID,
+				StudentName:    enrollment.User.Name,
+				LastActivityAt: enrollment.LastActivityAt,
+				DaysInactive:   "never active",
+				ActivityTime:   (time.Duration(enrollment.TotalActivityTime) * time.Second).String(),
+				CurrentScore:   enrollment.Grades.CurrentScore,
+				Reason:         strings.Join(reasons, ", "),
+			}
+			if lastActivity, err := time.Parse(time.RFC3339, enrollment.LastActivityAt); err == nil {
+				student.DaysInactive = fmt.Sprintf("%d", int(now.Sub(lastActivity).Hours()/24))
+			}
+			if section := sections[enrollment.CourseSectionID]; section != nil {
+				student.Section = section.SISSectionID
+				student.Teachers = section.TeacherNames()
+			}
+
+			_students = append(_students, student)
+		}
+
+		sort.SliceStable(_students, func(i, j int) bool {
+			if _students[i].Section != _students[j].Section {
+				return _students[i].Section < _students[j].Section
+			}
+			return _students[i].StudentName < _students[j].StudentName
+		})
+		students = append(students, _students...)
+	}
+
+	return students, nil
+}
+
+func inactivityReasons(enrollment *Enrollment, criteria InactivityCriteria, now time.Time) []string {
+	reasons := []string{}
+
+	if criteria.InactiveDays > 0 {
+		lastActivity, err := time.Parse(time.RFC3339, enrollment.LastActivityAt)
+		switch {
+		case err != nil:
+			reasons = append(reasons, "no activity")
+		case now.Sub(lastActivity) > time.Duration(criteria.InactiveDays)*24*time.Hour:
+			reasons = append(reasons, fmt.Sprintf("no activity in %d days", criteria.InactiveDays))
+		}
+	}
+
+	if criteria.MinActivityMinutes > 0 && enrollment.TotalActivityTime < criteria.MinActivityMinutes*60 {
+		reasons = append(reasons, fmt.Sprintf("less than %d minutes in course", criteria.MinActivityMinutes))
+	}
+
+	return reasons
+}
@@ -0,0 +1,255 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type StudentSummary struct {
+	ID                  int    `json:"id"`
+	PageViews           int    `json:"page_views"`
+	MaxPageViews        int    `json:"max_page_views"`
+	PageViewsLevel      string `json:"page_views_level"`
+	Participations      int    `json:"participations"`
+	MaxParticipations   int    `json:"max_participations"`
+	ParticipationsLevel string `json:"participations_level"`
+	TardinessBreakdown  struct {
+		Total    float32 `json:"total"`
+		OnTime   float32 `json:"on_time"`
+		Late     float32 `json:"late"`
+		Missing  float32 `json:"missing"`
+		Floating float32 `json:"floating"`
+	} `json:"tardiness_breakdown"`
+}
+
+// UserActivity holds a student's page views per hour and every participation in a course.
+type UserActivity struct {
+	PageViews      map[string]int `json:"page_views"`
+	Participations []struct {
+		CreatedAt string `json:"created_at"`
+		URL       string `json:"url"`
+	} `json:"participations"`
+}
+
+type StudentEngagement struct {
+	Qualification       string  `json:"qualification" csv:"Qualification"`
+	CourseName          string  `json:"course_name" csv:"Course"`
+	Term                string  `json:"term" csv:"Term"`
+	StudentID           string  `json:"student_id" csv:"Student ID"`
+	StudentName         string  `json:"student_name" csv:"Student Name"`
+	PageViews           int     `json:"page_views" csv:"Page Views"`
+	PageViewsLevel      string  `json:"page_views_level" csv:"Page Views Level"`
+	Participations      int     `json:"participations" csv:"Participations"`
+	ParticipationsLevel string  `json:"participations_level" csv:"Participations Level"`
+	OnTime              float32 `json:"on_time" csv:"On Time"`
+	Late                float32 `json:"late" csv:"Late"`
+	Missing             float32 `json:"missing" csv:"Missing"`
+	Floating            float32 `json:"floating" csv:"Floating"`
+	// Only filled when the per student activity is requested
+	ActiveDays          int    `json:"active_days" csv:"Active Days"`
+	LastPageViewAt      string `json:"last_page_view_at" csv:"Last Page View"`
+	LastParticipationAt string `json:"last_participation_at" csv:"Last Participation"`
+	// Set when the analytics of the course or the student couldn't be read
+	Error string `json:"error" csv:"Error"`
+}
+
+func (c *APIClient) GetStudentSummariesByCourseID(courseID int) ([]*StudentSummary, error) {
+	summaries := []*StudentSummary{}
+	requestURL := fmt.Sprintf("%s/courses/%d/analytics/student_summaries?page=1&per_page=%d", c.BaseURL, courseID, c.PageSize)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_summaries := []*StudentSummary{}
+		if err := json.Unmarshal(body, &_summaries); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		summaries = append(summaries, _summaries...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return summaries, nil
+}
+
+func (c *APIClient) GetUserActivity(courseID int, userID int) (*UserActivity, error) {
+	activity := &UserActivity{}
+
+	requestURL := fmt.Sprintf("%s/courses/%d/analytics/users/%d/activity", c.BaseURL, courseID, userID)
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a get request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on get request call")
+	}
+	defer res.Body.Close()
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if err := json.Unmarshal(body, activity); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+	return activity, nil
+}
+
+// GetStudentEngagementByAccountID combines the analytics summaries of every student in every course of the account.
+// withActivity adds active days and last activity but takes one more request per student.
+// A course or student whose analytics can't be read gets the error on its rows instead of failing the report.
+// opts of nil includes courses with student enrollments
+func (c *Controller) GetStudentEngagementByAccountID(accountID int, opts *CourseQueryOptions, withActivity bool) ([]*StudentEngagement, error) {
+	engagement := []*StudentEngagement{}
+	if opts == nil {
+		opts = DefaultCourseQueryOptions()
+	}
+
+	courses, err := c.APIClient.GetCoursesByAccountID(accountID, opts)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving courses")
+	}
+
+	// Analytics are only kept for published courses
+	published := []*Course{}
+	for _, course := range courses {
+		if course.WorkflowState == string(AvailableCourse) {
+			published = append(published, course)
+		}
+	}
+
+	engagementByCourse := make([][]*StudentEngagement, len(published))
+	err = c.APIClient.forEachCourse(published, func(i int, course *Course) error {
+		engagementByCourse[i] = c.getStudentEngagementByCourse(course, withActivity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rows := range engagementByCourse {
+		engagement = append(engagement, rows...)
+	}
+
+	return engagement, nil
+}
+
+// getStudentEngagementByCourse returns a row per student with analytics. Without analytics every enrolled
+// student gets a row with the error, or the course gets one if the enrollments can't be read either.
+func (c *Controller) getStudentEngagementByCourse(course *Course, withActivity bool) []*StudentEngagement {
+	newRow := func() *StudentEngagement {
+		return &StudentEngagement{
+			Qualification: course.Account.Name,
+			CourseName:    course.Name,
+			Term:          course.TermName(),
+		}
+	}
+
+	enrollments, err := c.APIClient.GetEnrollmentsByCourseID(course.ID, StudentEnrollment)
+	if err != nil {
+		row := newRow()
+		row.Error = fmt.Sprintf("cannot read enrollments: %s", err)
+		return []*StudentEngagement{row}
+	}
+
+	students := make(map[int]*Enrollment)
+	for _, enrollment := range enrollments {
+		students[enrollment.UserID] = enrollment
+	}
+
+	summaries, err := c.APIClient.GetStudentSummariesByCourseID(course.ID)
+	if err != nil {
+		rows := []*StudentEngagement{}
+		for _, enrollment := range enrollments {
+			row := newRow()
+			row.StudentID = enrollment.User.SISUserID
+			row.StudentName = enrollment.User.Name
+			row.Error = fmt.Sprintf("cannot read course analytics: %s", err)
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	rows := []*StudentEngagement{}
+	for _, summary := range summaries {
+		row := newRow()
+		row.PageViews = summary.PageViews
+		row.PageViewsLevel = summary.PageViewsLevel
+		row.Participations = summary.Participations
+		row.ParticipationsLevel = summary.ParticipationsLevel
+		row.OnTime = summary.TardinessBreakdown.OnTime
+		row.Late = summary.TardinessBreakdown.Late
+		row.Missing = summary.TardinessBreakdown.Missing
+		row.Floating = summary.TardinessBreakdown.Floating
+		if student := students[summary.ID]; student != nil {
+			row.StudentID = student.User.SISUserID
+			row.StudentName = student.User.Name
+		}
+		rows = append(rows, row)
+
+		if !withActivity {
+			continue
+		}
+
+		activity, err := c.APIClient.GetUserActivity(course.ID, summary.ID)
+		if err != nil {
+			row.Error = fmt.Sprintf("cannot read activity: %s", err)
+			continue
+		}
+
+		days := make(map[string]bool)
+		for hour := range activity.PageViews {
+			// Keys are hours like 2024-03-05T13:00:00+08:00
+			days[hour[:min(len(hour), 10)]] = true
+			if hour > row.LastPageViewAt {
+				row.LastPageViewAt = hour
+			}
+		}
+		row.ActiveDays = len(days)
+		for _, participation := range activity.Participations {
+			if participation.CreatedAt > row.LastParticipationAt {
+				row.LastParticipationAt = participation.CreatedAt
+			}
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,196 @@
+package canvas
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TaskProgress is reported while a long running workflow goes through its students.
+type TaskProgress struct {
+	Task    string `json:"task"`
+	Done    int    `json:"done"`
+	Total   int    `json:"total"`
+	Message string `json:"message"`
+}
+
+type StudentAssessments struct {
+	Identifier  string              `json:"identifier"`
+	User        *User               `json:"user"`
+	Enrollments []*EnrollmentResult `json:"enrollments"`
+	Assignments []*AssignmentResult `json:"assignments"`
+	// Set when the results of this student couldn't be fetched, the other students are still gathered
+	Error string `json:"error"`
+}
+
+type StudentAssessmentsReport struct {
+	Students []*StudentAssessments `json:"students"`
+	NotFound []*UnresolvedUser     `json:"not_found"`
+}
+
+// StudentResultRow is one assignment of a student with the course grade, or one course without
+// assignments, so a whole class list fits in one CSV.
+type StudentResultRow struct {
+	Identifier    string  `json:"identifier" csv:"Identifier"`
+	StudentID     string  `json:"student_id" csv:"Student ID"`
+	StudentName   string  `json:"student_name" csv:"Student Name"`
+	Qualification string  `json:"qualification" csv:"Qualification"`
+	CourseName    string  `json:"course_name" csv:"Course Name"`
+	Term          string  `json:"term" csv:"Term"`
+	CourseStatus  string  `json:"course_status" csv:"Course Status"`
+	CurrentGrade  string  `json:"current_grade" csv:"Current Grade"`
+	CurrentScore  float32 `json:"current_score" csv:"Current Score"`
+	Assignment    string  `json:"assignment" csv:"Assignment"`
+	Score         float32 `json:"score" csv:"Score"`
+	MaxScore      float32 `json:"max_score" csv:"Max Score"`
+	SubmittedAt   string  `json:"submitted_at" csv:"Submitted At"`
+	DueAt         string  `json:"due_at" csv:"Due At"`
+	Status        string  `json:"status" csv:"Submission Status"`
+	Error         string  `json:"error" csv:"Error"`
+}
+
+const RosterResultsTask = "roster-results"
+
+func (c *Controller) progress(task string, done int, total int, message string) {
+	if c.OnProgress == nil {
+		return
+	}
+
+	c.OnProgress(&TaskProgress{
+		Task:    task,
+		Done:    done,
+		Total:   total,
+		Message: message,
+	})
+}
+
+// GetRosterResults gathers the enrollment and assignment results of each student of a class list, fetched
+// Concurrency at a time. Unknown students are listed in NotFound instead of failing the whole report.
+// accountID is only used for email lookups.
+func (c *Controller) GetRosterResults(accountID int, identifierType UserIdentifierType, identifiers []string) (*StudentAssessmentsReport, error) {
+	c.progress(RosterResultsTask, 0, len(identifiers), "resolving students")
+	resolution, err := c.APIClient.ResolveUsers(accountID, identifierType, identifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StudentAssessmentsReport{
+		Students: make([]*StudentAssessments, len(resolution.Users)),
+		NotFound: resolution.NotFound,
+	}
+
+	var mu sync.Mutex
+	done := 0
+	c.progress(RosterResultsTask, done, len(resolution.Users), "fetching results")
+	c.APIClient.forEach(len(resolution.Users), func(i int) error {
+		report.Students[i] = c.getStudentAssessments(resolution.Users[i])
+
+		mu.Lock()
+		done++
+		c.progress(RosterResultsTask, done, len(resolution.Users), fmt.Sprintf("fetched results of %s", resolution.Users[i].User.Name))
+		mu.Unlock()
+		return nil
+	})
+
+	return report, nil
+}
+
+func (c *Controller) getStudentAssessments(resolved *ResolvedUser) *StudentAssessments {
+	student := &StudentAssessments{
+		Identifier:  resolved.Identifier,
+		User:        resolved.User,
+		Enrollments: []*EnrollmentResult{},
+		Assignments: []*AssignmentResult{},
+	}
+
+	// Both fetches go through the rate limiter so they can run side by side
+	var enrollmentsErr, assignmentsErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		student.Enrollments, enrollmentsErr = c.APIClient.GetAllEnrollmentsResultsByUserID(resolved.User.ID)
+	}()
+	go func() {
+		defer wg.Done()
+		student.Assignments, assignmentsErr = c.APIClient.GetAssignmentsResultsByUser(resolved.User)
+	}()
+	wg.Wait()
+
+	switch {
+	case enrollmentsErr != nil:
+		student.Error = fmt.Sprintf("enrollment results: %s", enrollmentsErr)
+	case assignmentsErr != nil:
+		student.Error = fmt.Sprintf("assignment results: %s", assignmentsErr)
+	}
+	if student.Enrollments == nil {
+		student.Enrollments = []*EnrollmentResult{}
+	}
+	if student.Assignments == nil {
+		student.Assignments = []*AssignmentResult{}
+	}
+
+	return student
+}
+
+// Rows flattens the report into one row per assignment, with the course grade on every row.
+// Students that weren't found or failed get a row with the error.
+func (report *StudentAssessmentsReport) Rows() []*StudentResultRow {
+	rows := []*StudentResultRow{}
+	for _, student := range report.Students {
+		if student.Error != "" {
+			rows = append(rows, &StudentResultRow{
+				Identifier:  student.Identifier,
+				StudentID:   student.User.SISUserID,
+				StudentName: student.User.Name,
+				Error:       student.Error,
+			})
+			continue
+		}
+
+		// Course names repeat across terms, only the ID tells the courses apart
+		assignmentsByCourse := make(map[int][]*AssignmentResult)
+		for _, assignment := range student.Assignments {
+			assignmentsByCourse[assignment.CourseID] = append(assignmentsByCourse[assignment.CourseID], assignment)
+		}
+
+		for _, enrollment := range student.Enrollments {
+			course := &StudentResultRow{
+				Identifier:    student.Identifier,
+				StudentID:     enrollment.StudentID,
+				StudentName:   enrollment.StudentName,
+				Qualification: enrollment.Qualification,
+				CourseName:    enrollment.CourseName,
+				Term:          enrollment.Term,
+				CourseStatus:  enrollment.CourseStatus,
+				CurrentGrade:  enrollment.CurrentGrade,
+				CurrentScore:  enrollment.CurrentScore,
+			}
+
+			assignments := assignmentsByCourse[enrollment.CourseID]
+			if len(assignments) == 0 {
+				rows = append(rows, course)
+				continue
+			}
+
+			for _, assignment := range assignments {
+				row := *course
+				row.Assignment = assignment.Title
+				row.Score = assignment.Submission.Score
+				row.MaxScore = assignment.MaxScore
+				row.SubmittedAt = assignment.Submission.SubmittedAt
+				row.DueAt = assignment.DueAt
+				row.Status = assignment.Status
+				rows = append(rows, &row)
+			}
+		}
+	}
+
+	for _, user := range report.NotFound {
+		rows = append(rows, &StudentResultRow{
+			Identifier: user.Identifier,
+			Error:      user.Reason,
+		})
+	}
+
+	return rows
+}
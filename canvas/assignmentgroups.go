@@ -0,0 +1,343 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type AssignmentGroup struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Position    int     `json:"position"`
+	GroupWeight float64 `json:"group_weight"`
+	Rules       struct {
+		DropLowest  int   `json:"drop_lowest"`
+		DropHighest int   `json:"drop_highest"`
+		NeverDrop   []int `json:"never_drop"`
+	} `json:"rules"`
+	Assignments []*GroupAssignment `json:"assignments"`
+}
+
+type GroupAssignment struct {
+	ID                 int     `json:"id"`
+	Name               string  `json:"name"`
+	PointsPossible     float64 `json:"points_possible"`
+	Published          bool    `json:"published"`
+	OmitFromFinalGrade bool    `json:"omit_from_final_grade"`
+}
+
+// GroupSubtotal is a student's result in one assignment group, scores are percentages.
+type GroupSubtotal struct {
+	Group          string   `json:"group"`
+	Weight         float64  `json:"weight"`
+	Points         float64  `json:"points"`
+	PointsPossible float64  `json:"points_possible"`
+	Dropped        int      `json:"dropped"`
+	CurrentScore   *float64 `json:"current_score"`
+	FinalScore     *float64 `json:"final_score"`
+}
+
+// GradeBreakdown compares the grade recomputed from submissions with the one Canvas reports.
+type GradeBreakdown struct {
+	UserID             int              `json:"user_id"`
+	StudentID          string           `json:"student_id"`
+	StudentName        string           `json:"student_name"`
+	CourseName         string           `json:"course_name"`
+	Weighted           bool             `json:"weighted"`
+	Groups             []*GroupSubtotal `json:"groups"`
+	CurrentScore       *float64         `json:"current_score"`
+	FinalScore         *float64         `json:"final_score"`
+	CanvasCurrentScore float32          `json:"canvas_current_score"`
+	CanvasFinalScore   float32          `json:"canvas_final_score"`
+	Mismatch           bool             `json:"mismatch"`
+	MismatchDetails    string           `json:"mismatch_details"`
+}
+
+// Scores within this many percentage points of Canvas are not a mismatch
+const gradeTolerance = 0.01
+
+func (c *APIClient) GetAssignmentGroupsByCourseID(courseID int) ([]*AssignmentGroup, error) {
+	groups := []*AssignmentGroup{}
+	requestURL := fmt.Sprintf("%s/courses/%d/assignment_groups?page=1&per_page=%d&include[]=assignments", c.BaseURL, courseID, c.PageSize)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_groups := []*AssignmentGroup{}
+		if err := json.Unmarshal(body, &_groups); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		groups = append(groups, _groups...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return groups, nil
+}
+
+// GetGradeBreakdownsByCourse recomputes every student's group subtotals and total from their submissions.
+func (c *Controller) GetGradeBreakdownsByCourse(courseID int) ([]*GradeBreakdown, error) {
+	breakdowns := []*GradeBreakdown{}
+
+	course, err := c.APIClient.GetCourseByID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving course")
+	}
+
+	groups, err := c.APIClient.GetAssignmentGroupsByCourseID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving assignment groups")
+	}
+
+	enrollments, err := c.APIClient.GetEnrollmentsByCourseID(courseID, StudentEnrollment)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving enrollments")
+	}
+
+	submissions, err := c.APIClient.GetSubmissionsByCourseID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving submissions")
+	}
+
+	submissionsByUser := make(map[int][]*Submission)
+	for _, submission := range submissions {
+		submissionsByUser[submission.UserID] = append(submissionsByUser[submission.UserID], submission)
+	}
+
+	for _, enrollment := range enrollments {
+		breakdown := ComputeGrade(groups, course.ApplyAssignmentGroupWeights, submissionsByUser[enrollment.UserID])
+		breakdown.UserID = enrollment.UserID
+		breakdown.StudentID = enrollment.User.SISUserID
+		breakdown.StudentName = enrollment.User.Name
+		breakdown.CourseName = course.Name
+		breakdown.CanvasCurrentScore = enrollment.Grades.CurrentScore
+		breakdown.CanvasFinalScore = enrollment.Grades.FinalScore
+
+		details := []string{}
+		if !scoresMatch(breakdown.CurrentScore, breakdown.CanvasCurrentScore) {
+			details = append(details, fmt.Sprintf("current %s vs Canvas %.2f", formatScore(breakdown.CurrentScore), breakdown.CanvasCurrentScore))
+		}
+		if !scoresMatch(breakdown.FinalScore, breakdown.CanvasFinalScore) {
+			details = append(details, fmt.Sprintf("final %s vs Canvas %.2f", formatScore(breakdown.FinalScore), breakdown.CanvasFinalScore))
+		}
+		breakdown.Mismatch = len(details) > 0
+		breakdown.MismatchDetails = strings.Join(details, ", ")
+
+		breakdowns = append(breakdowns, breakdown)
+	}
+
+	return breakdowns, nil
+}
+
+// ComputeGrade works out group subtotals and the total the way the Canvas gradebook does:
+// unpublished and omitted assignments don't count, excused ones are left out, drop rules remove
+// the lowest or highest scores by percentage, and weights are scaled over the groups that have points.
+// The current score only counts graded submissions, the final score counts ungraded ones as zero.
+func ComputeGrade(groups []*AssignmentGroup, weighted bool, submissions []*Submission) *GradeBreakdown {
+	breakdown := &GradeBreakdown{
+		Weighted: weighted,
+		Groups:   []*GroupSubtotal{},
+	}
+
+	submissionsByAssignment := make(map[int]*Submission)
+	for _, submission := range submissions {
+		submissionsByAssignment[submission.AssignmentID] = submission
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Position < groups[j].Position
+	})
+
+	var current, final gradeTotals
+	for _, group := range groups {
+		currentScores := []groupScore{}
+		finalScores := []groupScore{}
+		for _, assignment := range group.Assignments {
+			submission := submissionsByAssignment[assignment.ID]
+			// No submission means the assignment isn't assigned to the student
+			if !assignment.Published || assignment.OmitFromFinalGrade || submission == nil || submission.Excused {
+				continue
+			}
+
+			score := groupScore{assignmentID: assignment.ID, possible: assignment.PointsPossible}
+			if submission.Score != nil {
+				score.points = float64(*submission.Score)
+				currentScores = append(currentScores, score)
+			}
+			finalScores = append(finalScores, score)
+		}
+
+		counted := len(finalScores)
+		currentScores = dropScores(group, currentScores)
+		finalScores = dropScores(group, finalScores)
+
+		subtotal := &GroupSubtotal{
+			Group:   group.Name,
+			Weight:  group.GroupWeight,
+			Dropped: counted - len(finalScores),
+		}
+		subtotal.Points, subtotal.PointsPossible = sumScores(finalScores)
+		subtotal.CurrentScore = current.add(group.GroupWeight, currentScores)
+		subtotal.FinalScore = final.add(group.GroupWeight, finalScores)
+
+		breakdown.Groups = append(breakdown.Groups, subtotal)
+	}
+
+	breakdown.CurrentScore = current.score(weighted)
+	breakdown.FinalScore = final.score(weighted)
+
+	return breakdown
+}
+
+type groupScore struct {
+	assignmentID int
+	points       float64
+	possible     float64
+}
+
+func (s groupScore) percent() float64 {
+	if s.possible == 0 {
+		return 0
+	}
+
+	return s.points / s.possible
+}
+
+type gradeTotals struct {
+	points   float64
+	possible float64
+	weighted float64
+	weights  float64
+}
+
+// add counts a group towards the total and returns its percentage, nil when it has no points possible.
+func (t *gradeTotals) add(weight float64, scores []groupScore) *float64 {
+	points, possible := sumScores(scores)
+	t.points += points
+	t.possible += possible
+
+	if possible == 0 {
+		return nil
+	}
+
+	percent := points / possible * 100
+	t.weighted += percent * weight
+	t.weights += weight
+
+	return &percent
+}
+
+func (t *gradeTotals) score(weighted bool) *float64 {
+	var score float64
+	switch {
+	case weighted && t.weights > 0:
+		score = t.weighted / t.weights
+	case !weighted && t.possible > 0:
+		score = t.points / t.possible * 100
+	default:
+		return nil
+	}
+
+	score = math.Round(score*100) / 100
+	return &score
+}
+
+func sumScores(scores []groupScore) (float64, float64) {
+	var points, possible float64
+	for _, score := range scores {
+		points += score.points
+		possible += score.possible
+	}
+
+	return points, possible
+}
+
+// dropScores applies the group's drop rules, always keeping at least one score.
+func dropScores(group *AssignmentGroup, scores []groupScore) []groupScore {
+	if group.Rules.DropLowest == 0 && group.Rules.DropHighest == 0 {
+		return scores
+	}
+
+	kept := []groupScore{}
+	droppable := []groupScore{}
+	for _, score := range scores {
+		neverDrop := false
+		for _, id := range group.Rules.NeverDrop {
+			if id == score.assignmentID {
+				neverDrop = true
+			}
+		}
+
+		if neverDrop {
+			kept = append(kept, score)
+		} else {
+			droppable = append(droppable, score)
+		}
+	}
+
+	sort.SliceStable(droppable, func(i, j int) bool {
+		return droppable[i].percent() < droppable[j].percent()
+	})
+
+	lowest := min(group.Rules.DropLowest, len(droppable))
+	if len(kept) == 0 && lowest > 0 && lowest == len(droppable) {
+		lowest--
+	}
+	droppable = droppable[lowest:]
+
+	highest := min(group.Rules.DropHighest, len(droppable))
+	if len(kept) == 0 && highest > 0 && highest == len(droppable) {
+		highest--
+	}
+	droppable = droppable[:len(droppable)-highest]
+
+	return append(kept, droppable...)
+}
+
+func scoresMatch(computed *float64, canvas float32) bool {
+	if computed == nil {
+		return canvas == 0
+	}
+
+	return math.Abs(*computed-float64(canvas)) <= gradeTolerance
+}
+
+func formatScore(score *float64) string {
+	if score == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%.2f", *score)
+}
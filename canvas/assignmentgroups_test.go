@@ -0,0 +1,54 @@
+package canvas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDropScores(t *testing.T) {
+	scores := []groupScore{
+		{assignmentID: 1, points: 5, possible: 10},
+		{assignmentID: 2, points: 9, possible: 10},
+		{assignmentID: 3, points: 7, possible: 10},
+	}
+
+	tests := []struct {
+		name        string
+		dropLowest  int
+		dropHighest int
+		neverDrop   []int
+		scores      []groupScore
+		want        []int
+	}{
+		{name: "no rules", scores: scores, want: []int{1, 2, 3}},
+		{name: "empty", dropLowest: 1, dropHighest: 1, scores: []groupScore{}, want: []int{}},
+		{name: "all ungraded", dropLowest: 1, scores: nil, want: []int{}},
+		{name: "all ungraded drop highest", dropHighest: 1, scores: nil, want: []int{}},
+		{name: "drop lowest", dropLowest: 1, scores: scores, want: []int{3, 2}},
+		{name: "drop highest", dropHighest: 1, scores: scores, want: []int{1, 3}},
+		{name: "drop lowest and highest", dropLowest: 1, dropHighest: 1, scores: scores, want: []int{3}},
+		{name: "drop lowest of count keeps one", dropLowest: 3, scores: scores, want: []int{2}},
+		{name: "drop lowest over count keeps one", dropLowest: 5, scores: scores, want: []int{2}},
+		{name: "drop highest over count keeps one", dropHighest: 5, scores: scores, want: []int{1}},
+		{name: "drop both over count keeps one", dropLowest: 5, dropHighest: 5, scores: scores, want: []int{2}},
+		{name: "single score", dropLowest: 1, dropHighest: 1, scores: scores[:1], want: []int{1}},
+		{name: "never drop", dropLowest: 5, neverDrop: []int{1}, scores: scores, want: []int{1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			group := &AssignmentGroup{}
+			group.Rules.DropLowest = test.dropLowest
+			group.Rules.DropHighest = test.dropHighest
+			group.Rules.NeverDrop = test.neverDrop
+
+			got := []int{}
+			for _, score := range dropScores(group, test.scores) {
+				got = append(got, score.assignmentID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("dropScores() kept %v, want %v", got, test.want)
+			}
+		})
+	}
+}
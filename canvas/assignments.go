@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 
@@ -41,6 +42,7 @@ type Assignment struct {
 	CourseID                   int                    `json:"course_id" csv:"-"`
 	Account                    string                 `json:"qualification" csv:"Qualification"`
 	CourseName                 string                 `json:"course_name" csv:"Course Name"`
+	Term                       string                 `json:"term" csv:"Term"`
 	Name                       string                 `json:"name" csv:"Assignment"`
 	DueAt                      string                 `json:"due_at" csv:"Due"`
 	UnlockAt                   string                 `json:"unlock_at" csv:"Available From"`
@@ -49,11 +51,16 @@ type Assignment struct {
 	Section                    string                 `json:"section" csv:"Section"`
 	NeedingGradingSection      int                    `json:"needs_grading_section" csv:"Needs Grading"`
 	Teachers                   string                 `json:"teachers" csv:"Teachers"`
+	SectionTeachers            []*SectionTeacher      `json:"section_teachers" csv:"-"`
 	Status                     string                 `json:"status" csv:"Status"`
 	Published                  bool                   `json:"published" csv:"Published"`
 	GradebookURL               string                 `json:"gradebook_url" csv:"Gradebook URL"`
 	NeedsGradingCountBySection []*SectionNeedsGrading `json:"needs_grading_count_by_section" csv:"-"`
 	AllDates                   []*AssignmentDate      `json:"all_dates" csv:"-"`
+	Rubric                     []*RubricCriterion     `json:"rubric" csv:"-"`
+	QuizID                     int                    `json:"quiz_id" csv:"-"`
+	IsQuizAssignment           bool                   `json:"is_quiz_assignment" csv:"-"`
+	IsQuizLTIAssignment        bool                   `json:"is_quiz_lti_assignment" csv:"-"`
 }
 
 type SectionNeedsGrading struct {
@@ -63,10 +70,12 @@ type SectionNeedsGrading struct {
 
 type AssignmentResult struct {
 	AssignmentID  int     `json:"assignment_id" csv:"-"`
+	CourseID      int     `json:"course_id" csv:"-"`
 	UserSisID     string  `csv:"Student ID"`
 	StudentName   string  `csv:"Student Name"`
 	Qualification string  `csv:"Qualification"`
 	CourseName    string  `csv:"Course Name"`
+	Term          string  `csv:"Term"`
 	Title         string  `json:"title" csv:"Assignment"`
 	MaxScore      float32 `json:"max_score" csv:"Max Score"`
 	MinScore      float32 `json:"min_score" csv:"Min Score"`
@@ -76,6 +85,13 @@ type AssignmentResult struct {
 	} `json:"submission"`
 	DueAt  string `json:"due-at" csv:"Due At"`
 	Status string `json:"status" csv:"Submission Status"`
+	// Only set for classic and New Quizzes
+	QuizType  QuizType `json:"quiz_type" csv:"Quiz Type"`
+	Attempts  int      `json:"attempts" csv:"Attempts"`
+	TimeSpent string   `json:"time_spent" csv:"Time Spent"`
+	KeptScore *float32 `json:"kept_score" csv:"Kept Score"`
+	// Set when the quiz columns of the row couldn't be read
+	QuizError string `json:"quiz_error" csv:"Quiz Error"`
 }
 
 type AssignmentDate struct {
@@ -90,7 +106,14 @@ type AssignmentDate struct {
 
 func (c *APIClient) GetAssignmentsResultsByUser(user *User) ([]*AssignmentResult, error) {
 	results := []*AssignmentResult{}
-	enrollments, err := c.GetEnrollmentsByUserID(user.ID)
+	courses := make(map[int]*Course)
+	var enrollments []*Enrollment
+	var err error
+	if c.UseGraphQL {
+		enrollments, courses, err = c.getEnrollmentsWithCoursesGraphQL(user.ID)
+	} else {
+		enrollments, err = c.GetEnrollmentsByUserID(user.ID)
+	}
 	if err != nil {
 		return nil, terror.Error(err, fmt.Sprintf("cannot get enrollments of user ID: %s", user.SISUserID))
 	}
@@ -117,9 +140,12 @@ func (c *APIClient) GetAssignmentsResultsByUser(user *User) ([]*AssignmentResult
 			continue
 		}
 
-		course, err := c.GetCourseByID(enrollment.CourseID)
-		if err != nil {
-			terror.Error(err, "error fetching course")
+		course := courses[enrollment.CourseID]
+		if course == nil {
+			course, err = c.GetCourseByID(enrollment.CourseID)
+			if err != nil {
+				terror.Error(err, "error fetching course")
+			}
 		}
 
 		body, err := io.ReadAll(res.Body)
@@ -133,11 +159,18 @@ func (c *APIClient) GetAssignmentsResultsByUser(user *User) ([]*AssignmentResult
 		}
 
 		for _, result := range ars {
+			result.CourseID = enrollment.CourseID
 			result.CourseName = course.Name
+			result.Term = course.TermName()
 			result.UserSisID = user.SISUserID
 			result.StudentName = user.Name
 		}
 
+		err = c.addQuizResults(enrollment.CourseID, user.ID, ars)
+		if err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("cannot get quiz results of course ID: %d", enrollment.CourseID))
+		}
+
 		results = append(results, ars...)
 	}
 
@@ -151,6 +184,14 @@ func (c *APIClient) GetAssignmentsByCourse(course *Course, bucket AssignmentBuck
 	sections := make(map[int]*SectionWithEnrollments)
 	trimmedBaseURL := strings.TrimSuffix(c.BaseURL, "/api/v1")
 
+	if c.UseGraphQL {
+		_sections, err := c.getSectionsWithTeachersGraphQL(course.ID)
+		if err != nil {
+			return nil, terror.Error(err, "error retreiving sections")
+		}
+		sections = _sections
+	}
+
 	for {
 		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 		if err != nil {
@@ -176,17 +217,6 @@ func (c *APIClient) GetAssignmentsByCourse(course *Course, bucket AssignmentBuck
 		}
 
 		for _, _assignment := range _assignments {
-			dates := make(map[int]*AssignmentDate)
-			for _, date := range _assignment.AllDates {
-				if date.SetID != 0 {
-					dates[date.SetID] = date
-				}
-			}
-
-			if len(_assignment.NeedsGradingCountBySection) == 0 {
-				fmt.Println("hello no sections")
-			}
-
 			for _, section := range _assignment.NeedsGradingCountBySection {
 				if sections[section.SectionID] == nil {
 					enrollments, err := c.GetEnrollmentsBySectionID(section.SectionID, TeacherEnrollment)
@@ -204,23 +234,23 @@ func (c *APIClient) GetAssignmentsByCourse(course *Course, bucket AssignmentBuck
 							sections[section.SectionID] = &SectionWithEnrollments{
 								ID:           _section.ID,
 								SISSectionID: _section.Name,
-								Teachers:     []string{},
+								Teachers:     []*SectionTeacher{},
 							}
 
 						} else {
 							sections[section.SectionID] = &SectionWithEnrollments{
 								ID:           _section.ID,
 								SISSectionID: _section.SISSectionID,
-								Teachers:     []string{},
+								Teachers:     []*SectionTeacher{},
 							}
 						}
 
 						continue
 					}
 
-					teachers := []string{}
+					teachers := []*SectionTeacher{}
 					for _, enrollment := range enrollments {
-						teachers = append(teachers, enrollment.User.Name)
+						teachers = append(teachers, &SectionTeacher{ID: enrollment.UserID, Name: enrollment.User.Name})
 					}
 
 					if enrollments[0].SISSectionID == "" {
@@ -243,33 +273,7 @@ func (c *APIClient) GetAssignmentsByCourse(course *Course, bucket AssignmentBuck
 					}
 				}
 
-				assignment := &Assignment{
-					ID:                         _assignment.ID,
-					CourseID:                   _assignment.CourseID,
-					Name:                       _assignment.Name,
-					NeedsGradingCount:          _assignment.NeedsGradingCount,
-					Section:                    sections[section.SectionID].SISSectionID,
-					NeedingGradingSection:      section.NeedsGradingCount,
-					Teachers:                   strings.Join(sections[section.SectionID].Teachers, ";"),
-					Published:                  _assignment.Published,
-					NeedsGradingCountBySection: _assignment.NeedsGradingCountBySection,
-					Account:                    course.Account.Name,
-					CourseName:                 course.Name,
-					Status:                     string(bucket),
-					GradebookURL:               fmt.Sprintf(`%s/courses/%d/gradebook`, trimmedBaseURL, course.ID),
-				}
-
-				// Date set type is ADHOC
-				if dates[section.SectionID] == nil {
-					assignment.DueAt = ""
-					assignment.LockAt = ""
-					assignment.UnlockAt = ""
-				} else {
-					assignment.DueAt = dates[section.SectionID].DueAt
-					assignment.LockAt = dates[section.SectionID].LockAt
-					assignment.UnlockAt = dates[section.SectionID].UnlockAt
-				}
-
+				assignment := NewSectionAssignment(course, _assignment, section, sections[section.SectionID], bucket, trimmedBaseURL)
 				assignments = append(assignments, assignment)
 			}
 		}
@@ -285,23 +289,123 @@ func (c *APIClient) GetAssignmentsByCourse(course *Course, bucket AssignmentBuck
 	return assignments, nil
 }
 
+// NewSectionAssignment is the report row of an assignment in one section, with the dates of the section.
+// baseURL is the Canvas URL without /api/v1.
+func NewSectionAssignment(course *Course, assignment *Assignment, section *SectionNeedsGrading, directory *SectionWithEnrollments, bucket AssignmentBucket, baseURL string) *Assignment {
+	row := &Assignment{
+		ID:                         assignment.ID,
+		CourseID:                   assignment.CourseID,
+		Name:                       assignment.Name,
+		NeedsGradingCount:          assignment.NeedsGradingCount,
+		Section:                    directory.SISSectionID,
+		NeedingGradingSection:      section.NeedsGradingCount,
+		Teachers:                   directory.TeacherNames(),
+		SectionTeachers:            directory.Teachers,
+		Published:                  assignment.Published,
+		NeedsGradingCountBySection: assignment.NeedsGradingCountBySection,
+		Account:                    course.Account.Name,
+		CourseName:                 course.Name,
+		Term:                       course.TermName(),
+		Status:                     string(bucket),
+		GradebookURL:               fmt.Sprintf(`%s/courses/%d/gradebook`, baseURL, course.ID),
+	}
+
+	// Dates without a section are ADHOC or the everyone else date and are left empty
+	for _, date := range assignment.AllDates {
+		if date.SetID != 0 && date.SetID == section.SectionID {
+			row.DueAt = date.DueAt
+			row.LockAt = date.LockAt
+			row.UnlockAt = date.UnlockAt
+		}
+	}
+
+	return row
+}
+
 // bucket allowed values: past, overdue, undated, ungraded, unsubmitted, upcoming, future
-func (c *APIClient) GetAssignmentsByAccount(account *Account, bucket AssignmentBucket) ([]*Assignment, error) {
+// opts of nil includes courses with student enrollments
+func (c *APIClient) GetAssignmentsByAccount(account *Account, bucket AssignmentBucket, opts *CourseQueryOptions) ([]*Assignment, error) {
 	assignments := []*Assignment{}
+	if opts == nil {
+		opts = DefaultCourseQueryOptions()
+	}
 
-	courses, err := c.GetCoursesByAccount(account, StudenCourseEnrollment)
+	courses, err := c.GetCoursesByAccount(account, opts)
 	if err != nil {
 		return nil, terror.Error(err, "error retrieving courses")
 	}
 
-	for _, course := range courses {
-		_assignments, err := c.GetAssignmentsByCourse(course, UngradedBucket)
+	assignmentsByCourse := make([][]*Assignment, len(courses))
+	err = c.forEachCourse(courses, func(i int, course *Course) error {
+		_assignments, err := c.GetAssignmentsByCourse(course, bucket)
 		if err != nil {
-			return nil, terror.Error(err, "error retrieving ungraded assignments")
+			return terror.Error(err, fmt.Sprintf("error retrieving %s assignments", bucket))
 		}
 
+		assignmentsByCourse[i] = _assignments
+		log.Println("Completed: ", account.Name, " ", course.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, _assignments := range assignmentsByCourse {
 		assignments = append(assignments, _assignments...)
-		fmt.Println("Completed: ", account.Name, " ", course.Name)
+	}
+
+	return assignments, nil
+}
+
+// GetAssignmentsByCourseID returns every assignment of the course, one row per assignment rather than per section.
+func (c *APIClient) GetAssignmentsByCourseID(courseID int) ([]*Assignment, error) {
+	return c.getAssignmentsByCourseID(courseID, "")
+}
+
+// GetAssignmentsWithSectionsByCourseID is GetAssignmentsByCourseID with the dates and needs grading counts of each section.
+func (c *APIClient) GetAssignmentsWithSectionsByCourseID(courseID int) ([]*Assignment, error) {
+	return c.getAssignmentsByCourseID(courseID, "&needs_grading_count_by_section=true&include[]=all_dates")
+}
+
+func (c *APIClient) getAssignmentsByCourseID(courseID int, query string) ([]*Assignment, error) {
+	assignments := []*Assignment{}
+	requestURL := fmt.Sprintf("%s/courses/%d/assignments?page=1&per_page=%d", c.BaseURL, courseID, c.PageSize) + query
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create http request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "cannot make http call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_assignments := []*Assignment{}
+		if err := json.Unmarshal(body, &_assignments); err != nil {
+			return nil, terror.Error(err, "cannot unmarshall response body")
+		}
+		assignments = append(assignments, _assignments...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
 	}
 
 	return assignments, nil
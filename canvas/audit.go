@@ -0,0 +1,198 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type GradeChangeScope string
+
+const (
+	AssignmentGradeChanges GradeChangeScope = "assignments"
+	CourseGradeChanges     GradeChangeScope = "courses"
+	StudentGradeChanges    GradeChangeScope = "students"
+	GraderGradeChanges     GradeChangeScope = "graders"
+)
+
+// For Wails EnumBind
+var AllGradeChangeScope = []struct {
+	Value  GradeChangeScope
+	TSName string
+}{
+	{AssignmentGradeChanges, "ASSIGNMENT"},
+	{CourseGradeChanges, "COURSE"},
+	{StudentGradeChanges, "STUDENT"},
+	{GraderGradeChanges, "GRADER"},
+}
+
+type GradeChangeEvent struct {
+	ID                string `json:"id"`
+	CreatedAt         string `json:"created_at"`
+	EventType         string `json:"event_type"`
+	GradeBefore       string `json:"grade_before"`
+	GradeAfter        string `json:"grade_after"`
+	ExcusedBefore     bool   `json:"excused_before"`
+	ExcusedAfter      bool   `json:"excused_after"`
+	GradedAnonymously bool   `json:"graded_anonymously"`
+	VersionNumber     int    `json:"version_number"`
+	RequestID         string `json:"request_id"`
+	Links             struct {
+		Assignment int `json:"assignment"`
+		Course     int `json:"course"`
+		Student    int `json:"student"`
+		Grader     int `json:"grader"`
+	} `json:"links"`
+}
+
+// GradeChangeLog holds the events of all pages with the objects they link to, keyed by ID.
+type GradeChangeLog struct {
+	Events      []*GradeChangeEvent
+	Assignments map[int]*Assignment
+	Courses     map[int]*Course
+	Users       map[int]*User
+}
+
+// GradeChangeRecord is an event joined with the names of what it links to.
+type GradeChangeRecord struct {
+	Time          string `json:"time" csv:"Time"`
+	EventType     string `json:"event_type" csv:"Event"`
+	CourseName    string `json:"course_name" csv:"Course"`
+	Assignment    string `json:"assignment" csv:"Assignment"`
+	StudentID     string `json:"student_id" csv:"Student ID"`
+	StudentName   string `json:"student_name" csv:"Student Name"`
+	GraderName    string `json:"grader_name" csv:"Changed By"`
+	GradeBefore   string `json:"grade_before" csv:"Grade Before"`
+	GradeAfter    string `json:"grade_after" csv:"Grade After"`
+	ExcusedBefore bool   `json:"excused_before" csv:"Excused Before"`
+	ExcusedAfter  bool   `json:"excused_after" csv:"Excused After"`
+	Anonymous     bool   `json:"anonymous" csv:"Graded Anonymously"`
+	RequestID     string `json:"request_id" csv:"Request ID"`
+}
+
+// GetGradeChanges reads the grade change log of an assignment, course, student or grader.
+// startTime and endTime are ISO 8601 and may be empty.
+func (c *APIClient) GetGradeChanges(scope GradeChangeScope, id int, startTime string, endTime string) (*GradeChangeLog, error) {
+	log := &GradeChangeLog{
+		Events:      []*GradeChangeEvent{},
+		Assignments: make(map[int]*Assignment),
+		Courses:     make(map[int]*Course),
+		Users:       make(map[int]*User),
+	}
+
+	query := url.Values{}
+	query.Set("page", "1")
+	query.Set("per_page", fmt.Sprintf("%d", c.PageSize))
+	if startTime != "" {
+		query.Set("start_time", startTime)
+	}
+	if endTime != "" {
+		query.Set("end_time", endTime)
+	}
+	requestURL := fmt.Sprintf("%s/audit/grade_change/%s/%d?%s", c.BaseURL, scope, id, query.Encode())
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		page := struct {
+			Events []*GradeChangeEvent `json:"events"`
+			Linked struct {
+				Assignments []*Assignment `json:"assignments"`
+				Courses     []*Course     `json:"courses"`
+				Users       []*User       `json:"users"`
+			} `json:"linked"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+
+		log.Events = append(log.Events, page.Events...)
+		for _, assignment := range page.Linked.Assignments {
+			log.Assignments[assignment.ID] = assignment
+		}
+		for _, course := range page.Linked.Courses {
+			log.Courses[course.ID] = course
+		}
+		for _, user := range page.Linked.Users {
+			log.Users[user.ID] = user
+		}
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return log, nil
+}
+
+// Records joins every event with its course, assignment, student and grader.
+// Automatic changes such as quiz grading have no grader.
+func (log *GradeChangeLog) Records() []*GradeChangeRecord {
+	records := []*GradeChangeRecord{}
+	for _, event := range log.Events {
+		record := &GradeChangeRecord{
+			Time:          event.CreatedAt,
+			EventType:     event.EventType,
+			GradeBefore:   event.GradeBefore,
+			GradeAfter:    event.GradeAfter,
+			ExcusedBefore: event.ExcusedBefore,
+			ExcusedAfter:  event.ExcusedAfter,
+			Anonymous:     event.GradedAnonymously,
+			RequestID:     event.RequestID,
+		}
+
+		if course := log.Courses[event.Links.Course]; course != nil {
+			record.CourseName = course.Name
+		}
+		if assignment := log.Assignments[event.Links.Assignment]; assignment != nil {
+			record.Assignment = assignment.Name
+		}
+		if student := log.Users[event.Links.Student]; student != nil {
+			record.StudentID = student.SISUserID
+			record.StudentName = student.Name
+		}
+		if grader := log.Users[event.Links.Grader]; grader != nil {
+			record.GraderName = grader.Name
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func (c *Controller) GetGradeChangeAudit(scope GradeChangeScope, id int, startTime string, endTime string) ([]*GradeChangeRecord, error) {
+	log, err := c.APIClient.GetGradeChanges(scope, id, startTime, endTime)
+	if err != nil {
+		return nil, terror.Error(err, fmt.Sprintf("error retrieving grade changes of %s ID: %d", scope, id))
+	}
+
+	return log.Records(), nil
+}
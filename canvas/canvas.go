@@ -2,11 +2,13 @@ package canvas
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ninja-software/terror/v2"
@@ -15,6 +17,8 @@ import (
 
 type Controller struct {
 	APIClient *APIClient
+	// OnProgress is called as long running workflows move along, e.g. to update the UI
+	OnProgress func(progress *TaskProgress)
 }
 
 func NewController(client *APIClient) *Controller {
@@ -29,6 +33,15 @@ type APIClient struct {
 	PageSize     int
 	Client       *http.Client
 	RateLimitter *rate.Limiter
+	// UseGraphQL lets report builders fetch course trees through the GraphQL API
+	// where it takes fewer requests than the REST endpoints.
+	UseGraphQL bool
+	// Journal records every write so it can be rolled back, writes fail without one.
+	Journal *Journal
+	// Concurrency is how many courses account reports fetch at once, 0 or 1 for one at a time.
+	// Requests still share the rate limiter.
+	Concurrency int
+	operation   *journalOperation
 }
 
 func NewAPIClient(baseURL string, accessToken string, pageSize int, client *http.Client, rateLimitter *rate.Limiter) *APIClient {
@@ -41,8 +54,19 @@ func NewAPIClient(baseURL string, accessToken string, pageSize int, client *http
 	}
 }
 
+var (
+	// ErrMissingToken is returned by every call of a client built without an access token, e.g. for offline commands
+	ErrMissingToken = errors.New("missing access token")
+	// ErrUnauthorized is returned when Canvas answers 401, the token is wrong or expired
+	ErrUnauthorized = errors.New("access token rejected")
+)
+
 // https://medium.com/mflow/rate-limiting-in-golang-http-client-a22fba15861a
 func (c *APIClient) do(req *http.Request) (*http.Response, error) {
+	if c.AccessToken == "" || c.Client == nil || c.RateLimitter == nil {
+		return nil, terror.Error(ErrMissingToken, "canvas client is not configured")
+	}
+
 	ctx := context.Background()
 	err := c.RateLimitter.Wait(ctx)
 	if err != nil {
@@ -54,9 +78,64 @@ func (c *APIClient) do(req *http.Request) (*http.Response, error) {
 		return nil, terror.Error(err, "error sending HTTP request")
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, terror.Error(fmt.Errorf("%w: %s %s", ErrUnauthorized, req.Method, req.URL.Path), "canvas rejected the access token")
+	}
+
 	return resp, nil
 }
 
+// forEach calls fn for 0 to n-1 with up to Concurrency calls at a time.
+func (c *APIClient) forEach(n int, fn func(i int) error) error {
+	return ForEach(n, c.Concurrency, fn)
+}
+
+// ForEach calls fn for 0 to n-1 with up to concurrency calls at a time, 0 or 1 for one at a time.
+// The first error stops the calls that haven't started yet and is returned.
+func ForEach(n int, concurrency int, fn func(i int) error) error {
+	workers := max(concurrency, 1)
+	jobs := make(chan int)
+	errs := make(chan error, n)
+	done := make(chan struct{})
+	var once sync.Once
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(i); err != nil {
+					errs <- err
+					once.Do(func() { close(done) })
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-done:
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// forEachCourse gives fn the index of the course so results can be kept in course order.
+func (c *APIClient) forEachCourse(courses []*Course, fn func(i int, course *Course) error) error {
+	return c.forEach(len(courses), func(i int) error {
+		return fn(i, courses[i])
+	})
+}
+
 func getNextURL(linkTxt string) string {
 	url := ""
 	if linkTxt != "" {
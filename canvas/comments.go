@@ -0,0 +1,121 @@
+package canvas
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type SubmissionComment struct {
+	ID          int    `json:"id"`
+	AuthorID    int    `json:"author_id"`
+	AuthorName  string `json:"author_name"`
+	Comment     string `json:"comment"`
+	CreatedAt   string `json:"created_at"`
+	EditedAt    string `json:"edited_at"`
+	Attachments []struct {
+		ID          int    `json:"id"`
+		DisplayName string `json:"display_name"`
+		ContentType string `json:"content-type"`
+		URL         string `json:"url"`
+	} `json:"attachments"`
+}
+
+// SubmissionFeedback is one student's result and assessor comments for one assignment.
+type SubmissionFeedback struct {
+	Qualification string `json:"qualification" csv:"Qualification"`
+	CourseName    string `json:"course_name" csv:"Course"`
+	Term          string `json:"term" csv:"Term"`
+	StudentSISID  string `json:"student_id" csv:"Student ID"`
+	StudentName   string `json:"student_name" csv:"Student Name"`
+	Assignment    string `json:"assignment" csv:"Assignment"`
+	Grade         string `json:"grade" csv:"Grade"`
+	GradedAt      string `json:"graded_at" csv:"Graded At"`
+	CommentCount  int    `json:"comment_count" csv:"Comments"`
+	Authors       string `json:"authors" csv:"Comment Authors"`
+	LastCommentAt string `json:"last_comment_at" csv:"Last Comment At"`
+	Feedback      string `json:"feedback" csv:"Feedback"`
+	Attachments   string `json:"attachments" csv:"Attachments"`
+}
+
+// GetFeedbackByCourse returns the comments of every graded or commented submission in the course.
+func (c *Controller) GetFeedbackByCourse(courseID int) ([]*SubmissionFeedback, error) {
+	course, err := c.APIClient.GetCourseByID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving course")
+	}
+
+	return c.getFeedbackByCourse(course, course.Account.Name)
+}
+
+// opts of nil returns feedback of all courses of the qualification
+func (c *Controller) GetFeedbackByQualification(qualification Qualification, opts *CourseQueryOptions) ([]*SubmissionFeedback, error) {
+	feedback := []*SubmissionFeedback{}
+
+	courses, err := c.APIClient.GetCoursesByAccountID(qualification.AccountID, opts)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving courses")
+	}
+
+	for _, course := range courses {
+		_feedback, err := c.getFeedbackByCourse(course, qualification.Name)
+		if err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("error retrieving feedback of course ID: %d", course.ID))
+		}
+		feedback = append(feedback, _feedback...)
+	}
+
+	return feedback, nil
+}
+
+func (c *Controller) getFeedbackByCourse(course *Course, qualification string) ([]*SubmissionFeedback, error) {
+	feedback := []*SubmissionFeedback{}
+
+	submissions, err := c.APIClient.GetSubmissionsByCourseID(course.ID, SubmissionCommentsInclude, AssignmentInclude)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving submissions")
+	}
+
+	for _, submission := range submissions {
+		if submission.Grade == "" && len(submission.SubmissionComments) == 0 {
+			continue
+		}
+
+		row := &SubmissionFeedback{
+			Qualification: qualification,
+			CourseName:    course.Name,
+			Term:          course.TermName(),
+			StudentSISID:  submission.User.SISUserID,
+			StudentName:   submission.User.Name,
+			Assignment:    submission.Assignment.Name,
+			Grade:         submission.Grade,
+			GradedAt:      submission.GradedAt,
+			CommentCount:  len(submission.SubmissionComments),
+		}
+
+		authors := []string{}
+		comments := []string{}
+		attachments := []string{}
+		for _, comment := range submission.SubmissionComments {
+			if !slices.Contains(authors, comment.AuthorName) {
+				authors = append(authors, comment.AuthorName)
+			}
+			comments = append(comments, fmt.Sprintf("[%s] %s: %s", comment.CreatedAt, comment.AuthorName, comment.Comment))
+			for _, attachment := range comment.Attachments {
+				attachments = append(attachments, fmt.Sprintf("%s (%s)", attachment.DisplayName, attachment.URL))
+			}
+			if comment.CreatedAt > row.LastCommentAt {
+				row.LastCommentAt = comment.CreatedAt
+			}
+		}
+		row.Authors = strings.Join(authors, ", ")
+		row.Feedback = strings.Join(comments, "\n")
+		row.Attachments = strings.Join(attachments, "\n")
+
+		feedback = append(feedback, row)
+	}
+
+	return feedback, nil
+}
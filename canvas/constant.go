@@ -67,3 +67,7 @@ var Qualifications []Qualification = []Qualification{
 func (c *Controller) GetQualifications() []Qualification {
 	return Qualifications
 }
+
+func (c *Controller) GetCoursesByQualification(qualification Qualification, opts *CourseQueryOptions) ([]*Course, error) {
+	return c.APIClient.GetCoursesByAccountID(qualification.AccountID, opts)
+}
@@ -0,0 +1,239 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type Conversation struct {
+	ID            int    `json:"id"`
+	Subject       string `json:"subject"`
+	WorkflowState string `json:"workflow_state"`
+	LastMessage   string `json:"last_message"`
+	MessageCount  int    `json:"message_count"`
+	Participants  []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"participants"`
+}
+
+// CreateConversation sends a new inbox message to each recipient on their own, never a group conversation.
+// Messages are journaled as pending before they are sent and cannot be rolled back. The conversations are
+// nil only when nothing was sent, a journal error after sending is returned with them.
+func (c *APIClient) CreateConversation(recipientIDs []int, subject string, body string) ([]*Conversation, error) {
+	if err := c.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	operation := c.currentOperation("send conversation")
+	if err := c.Journal.Append(conversationEntries(operation, recipientIDs, subject, body, true)...); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for _, recipientID := range recipientIDs {
+		form.Add("recipients[]", strconv.Itoa(recipientID))
+	}
+	form.Set("subject", subject)
+	form.Set("body", body)
+	form.Set("force_new", "true")
+	form.Set("group_conversation", "false")
+
+	requestURL := fmt.Sprintf("%s/conversations", c.BaseURL)
+	req, err := http.NewRequest(http.MethodPost, requestURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a post request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on post request call")
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return nil, terror.Error(fmt.Errorf("status code: %d, body: %s", res.StatusCode, resBody), "something went wrong and did not receive 200 OK status")
+	}
+
+	conversations := []*Conversation{}
+	if err := json.Unmarshal(resBody, &conversations); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+
+	if err := c.Journal.Append(conversationEntries(operation, recipientIDs, subject, body, false)...); err != nil {
+		return conversations, terror.Error(err, "message was sent but the journal only has it as pending")
+	}
+
+	return conversations, nil
+}
+
+func conversationEntries(operation *journalOperation, recipientIDs []int, subject string, body string, pending bool) []*JournalEntry {
+	entries := []*JournalEntry{}
+	for _, recipientID := range recipientIDs {
+		entries = append(entries, &JournalEntry{
+			OperationID: operation.ID,
+			Operation:   operation.Description,
+			Time:        time.Now().Format(time.RFC3339),
+			Kind:        ConversationJournalEntry,
+			UserID:      recipientID,
+			Text:        subject + "\n\n" + body,
+			Pending:     pending,
+		})
+	}
+
+	return entries
+}
+
+// DefaultNudgeSubject and DefaultNudgeTemplate are used when no subject or template is given.
+// The template is executed with a GradingNudge.
+const DefaultNudgeSubject = "Submissions waiting to be graded"
+
+const DefaultNudgeTemplate = `Hi {{.TeacherName}},
+
+There are {{.NeedsGrading}} submissions waiting to be graded in your sections:
+{{range .Assignments}}
+- {{.CourseName}} / {{.Section}}: {{.Name}} ({{.NeedingGradingSection}} ungraded)
+  {{.GradebookURL}}{{end}}
+
+Thank you`
+
+type GradingNudge struct {
+	TeacherID    int           `json:"teacher_id" csv:"-"`
+	TeacherName  string        `json:"teacher_name" csv:"Teacher"`
+	Sections     int           `json:"sections" csv:"Sections"`
+	NeedsGrading int           `json:"needs_grading" csv:"Needs Grading"`
+	Subject      string        `json:"subject" csv:"Subject"`
+	Body         string        `json:"body" csv:"Message"`
+	Assignments  []*Assignment `json:"assignments" csv:"-"`
+	// Filled when the nudge is sent
+	Sent           bool   `json:"sent" csv:"Sent"`
+	ConversationID int    `json:"conversation_id" csv:"-"`
+	Error          string `json:"error" csv:"Error"`
+	// Set when the message was sent but couldn't be journaled as sent
+	JournalError string `json:"journal_error" csv:"Journal Error"`
+}
+
+type GradingNudgePlan struct {
+	Nudges []*GradingNudge `json:"nudges"`
+	// Rows with ungraded submissions but no teacher in the section, nobody can be nudged about them
+	Unassigned []*Assignment `json:"unassigned"`
+}
+
+// PreviewGradingNudges groups ungraded assignment rows, as returned for the ungraded bucket, by teacher and
+// renders each teacher's message. Nothing is sent. Empty subject or template falls back to the defaults.
+func (c *Controller) PreviewGradingNudges(assignments []*Assignment, subject string, bodyTemplate string) (*GradingNudgePlan, error) {
+	if subject == "" {
+		subject = DefaultNudgeSubject
+	}
+	if bodyTemplate == "" {
+		bodyTemplate = DefaultNudgeTemplate
+	}
+
+	tmpl, err := template.New("nudge").Parse(bodyTemplate)
+	if err != nil {
+		return nil, terror.Error(err, "cannot parse nudge template")
+	}
+
+	plan := &GradingNudgePlan{
+		Nudges:     []*GradingNudge{},
+		Unassigned: []*Assignment{},
+	}
+	nudges := make(map[int]*GradingNudge)
+	for _, assignment := range assignments {
+		if assignment.NeedingGradingSection == 0 {
+			continue
+		}
+
+		if len(assignment.SectionTeachers) == 0 {
+			plan.Unassigned = append(plan.Unassigned, assignment)
+			continue
+		}
+
+		for _, teacher := range assignment.SectionTeachers {
+			nudge := nudges[teacher.ID]
+			if nudge == nil {
+				nudge = &GradingNudge{
+					TeacherID:   teacher.ID,
+					TeacherName: teacher.Name,
+					Subject:     subject,
+				}
+				nudges[teacher.ID] = nudge
+				plan.Nudges = append(plan.Nudges, nudge)
+			}
+
+			nudge.Assignments = append(nudge.Assignments, assignment)
+			nudge.NeedsGrading += assignment.NeedingGradingSection
+		}
+	}
+
+	for _, nudge := range plan.Nudges {
+		sections := make(map[string]bool)
+		for _, assignment := range nudge.Assignments {
+			sections[fmt.Sprintf("%d/%s", assignment.CourseID, assignment.Section)] = true
+		}
+		nudge.Sections = len(sections)
+
+		body := &bytes.Buffer{}
+		if err := tmpl.Execute(body, nudge); err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("cannot render nudge for teacher: %s", nudge.TeacherName))
+		}
+		nudge.Body = body.String()
+	}
+
+	sort.SliceStable(plan.Nudges, func(i, j int) bool {
+		return plan.Nudges[i].TeacherName < plan.Nudges[j].TeacherName
+	})
+
+	return plan, nil
+}
+
+// SendGradingNudges sends each previewed nudge as its own conversation. A failed message is recorded on the
+// nudge and the rest are still sent. Nudges already sent are skipped, so a plan can be sent again after failures.
+func (c *Controller) SendGradingNudges(plan *GradingNudgePlan) ([]*GradingNudge, error) {
+	if err := c.APIClient.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	client := WithOperation(c.APIClient, fmt.Sprintf("grading nudges to %d teachers", len(plan.Nudges)))
+	for _, nudge := range plan.Nudges {
+		if nudge.Sent {
+			continue
+		}
+
+		conversations, err := client.CreateConversation([]int{nudge.TeacherID}, nudge.Subject, nudge.Body)
+		if conversations == nil {
+			nudge.Error = err.Error()
+			continue
+		}
+		nudge.Error = ""
+		if err != nil {
+			nudge.JournalError = err.Error()
+		}
+
+		nudge.Sent = true
+		if len(conversations) > 0 {
+			nudge.ConversationID = conversations[0].ID
+		}
+	}
+
+	return plan.Nudges, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/ninja-software/terror/v2"
 )
@@ -31,6 +33,135 @@ var AllCourseEnrollmentType = []struct {
 	{DesignerCourseEnrollment, "DESIGNER"},
 }
 
+type CourseState string
+
+const (
+	CreatedCourse   CourseState = "created"
+	ClaimedCourse   CourseState = "claimed"
+	AvailableCourse CourseState = "available"
+	CompletedCourse CourseState = "completed"
+	DeletedCourse   CourseState = "deleted"
+	AllCourse       CourseState = "all"
+	// Not a Canvas state, expands to created and claimed
+	UnpublishedCourse CourseState = "unpublished"
+)
+
+// For Wails EnumBind
+var AllCourseState = []struct {
+	Value  CourseState
+	TSName string
+}{
+	{CreatedCourse, "CREATED"},
+	{ClaimedCourse, "CLAIMED"},
+	{AvailableCourse, "AVAILABLE"},
+	{CompletedCourse, "COMPLETED"},
+	{DeletedCourse, "DELETED"},
+	{AllCourse, "ALL"},
+	{UnpublishedCourse, "UNPUBLISHED"},
+}
+
+type CourseSort string
+
+const (
+	CourseNameSort  CourseSort = "course_name"
+	SISCourseIDSort CourseSort = "sis_course_id"
+	TeacherSort     CourseSort = "teacher"
+	AccountNameSort CourseSort = "account_name"
+)
+
+// For Wails EnumBind
+var AllCourseSort = []struct {
+	Value  CourseSort
+	TSName string
+}{
+	{CourseNameSort, "COURSE_NAME"},
+	{SISCourseIDSort, "SIS_COURSE_ID"},
+	{TeacherSort, "TEACHER"},
+	{AccountNameSort, "ACCOUNT_NAME"},
+}
+
+// CourseQueryOptions filters account course listings, zero values are left out of the request.
+// https://canvas.instructure.com/doc/api/accounts.html#method.accounts.courses_api
+type CourseQueryOptions struct {
+	EnrollmentTypes  []CourseEnrollmentType `json:"enrollment_types"`
+	States           []CourseState          `json:"states"`
+	Published        *bool                  `json:"published"`
+	SearchTerm       string                 `json:"search_term"`
+	StartsBefore     string                 `json:"starts_before"`
+	EndsAfter        string                 `json:"ends_after"`
+	WithEnrollments  *bool                  `json:"with_enrollments"`
+	Blueprint        *bool                  `json:"blueprint"`
+	IncludeTeachers  bool                   `json:"include_teachers"`
+	Sort             CourseSort             `json:"sort"`
+	Order            string                 `json:"order"`
+	BySubaccounts    []int                  `json:"by_subaccounts"`
+	EnrollmentTermID int                    `json:"enrollment_term_id"`
+}
+
+// DefaultCourseQueryOptions returns courses with student enrollments, as the reports always did.
+func DefaultCourseQueryOptions() *CourseQueryOptions {
+	return &CourseQueryOptions{
+		EnrollmentTypes: []CourseEnrollmentType{StudenCourseEnrollment},
+	}
+}
+
+func (opts *CourseQueryOptions) values() url.Values {
+	values := url.Values{}
+	values.Add("include[]", "account")
+	values.Add("include[]", "term")
+
+	if opts == nil {
+		return values
+	}
+
+	if opts.IncludeTeachers {
+		values.Add("include[]", "teachers")
+	}
+	for _, enrollmentType := range opts.EnrollmentTypes {
+		values.Add("enrollment_type[]", string(enrollmentType))
+	}
+	for _, state := range opts.States {
+		if state == UnpublishedCourse {
+			values.Add("state[]", string(CreatedCourse))
+			values.Add("state[]", string(ClaimedCourse))
+			continue
+		}
+		values.Add("state[]", string(state))
+	}
+	if opts.Published != nil {
+		values.Set("published", strconv.FormatBool(*opts.Published))
+	}
+	if opts.SearchTerm != "" {
+		values.Set("search_term", opts.SearchTerm)
+	}
+	if opts.StartsBefore != "" {
+		values.Set("starts_before", opts.StartsBefore)
+	}
+	if opts.EndsAfter != "" {
+		values.Set("ends_after", opts.EndsAfter)
+	}
+	if opts.WithEnrollments != nil {
+		values.Set("with_enrollments", strconv.FormatBool(*opts.WithEnrollments))
+	}
+	if opts.Blueprint != nil {
+		values.Set("blueprint", strconv.FormatBool(*opts.Blueprint))
+	}
+	if opts.Sort != "" {
+		values.Set("sort", string(opts.Sort))
+	}
+	if opts.Order != "" {
+		values.Set("order", opts.Order)
+	}
+	for _, accountID := range opts.BySubaccounts {
+		values.Add("by_subaccounts[]", strconv.Itoa(accountID))
+	}
+	if opts.EnrollmentTermID != 0 {
+		values.Set("enrollment_term_id", strconv.Itoa(opts.EnrollmentTermID))
+	}
+
+	return values
+}
+
 type Course struct {
 	ID               int    `json:"id"`
 	Name             string `json:"name"`
@@ -43,17 +174,33 @@ type Course struct {
 	EndAt            string `json:"end_at"`
 	IsPublic         bool   `json:"is_public"`
 	EnrollmentTermID int    `json:"enrollment_term_id"`
-	Account          struct {
+	Term             *Term  `json:"term"`
+	Blueprint        bool   `json:"blueprint"`
+	// Total grade is weighted by assignment group
+	ApplyAssignmentGroupWeights bool `json:"apply_assignment_group_weights"`
+	Teachers                    []struct {
+		ID          int    `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"teachers"`
+	Account struct {
 		ID            int    `json:"id"`
 		Name          string `json:"name"`
 		WorkflowState string `json:"workflow_state"`
 	} `json:"account"`
 }
 
+func (course *Course) TermName() string {
+	if course.Term == nil {
+		return ""
+	}
+
+	return course.Term.Name
+}
+
 func (c *APIClient) GetCourseByID(id int) (*Course, error) {
 	course := &Course{}
 
-	requestURL := fmt.Sprintf("%s/courses/%d?include[]=account", c.BaseURL, id)
+	requestURL := fmt.Sprintf("%s/courses/%d?include[]=account&include[]=term", c.BaseURL, id)
 	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, terror.Error(err, "cannot create a get request")
@@ -82,10 +229,10 @@ func (c *APIClient) GetCourseByID(id int) (*Course, error) {
 	return course, nil
 }
 
-// enrollmentType allowed values: teacher, student, ta, observer, designer
-func (c *APIClient) GetCoursesByAccount(account *Account, enrollmentType CourseEnrollmentType) ([]*Course, error) {
+// opts of nil returns all courses of the account
+func (c *APIClient) GetCoursesByAccount(account *Account, opts *CourseQueryOptions) ([]*Course, error) {
 	courses := []*Course{}
-	requestURL := fmt.Sprintf("%s/accounts/%d/courses?page=1&per_page=%d&enrollment_type[]=%s&include[]=account", c.BaseURL, account.ID, c.PageSize, enrollmentType)
+	requestURL := fmt.Sprintf("%s/accounts/%d/courses?page=1&per_page=%d&%s", c.BaseURL, account.ID, c.PageSize, opts.values().Encode())
 
 	for {
 		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
@@ -123,10 +270,10 @@ func (c *APIClient) GetCoursesByAccount(account *Account, enrollmentType CourseE
 	return courses, nil
 }
 
-// enrollmentType allowed values: teacher, student, ta, observer, designer
-func (c *APIClient) GetCoursesByAccountID(accountID int, enrollmentType CourseEnrollmentType) ([]*Course, error) {
+// opts of nil returns all courses of the account
+func (c *APIClient) GetCoursesByAccountID(accountID int, opts *CourseQueryOptions) ([]*Course, error) {
 	courses := []*Course{}
-	requestURL := fmt.Sprintf("%s/accounts/%d/courses?page=1&per_page=%d&enrollment_type[]=%s&include[]=account", c.BaseURL, accountID, c.PageSize, enrollmentType)
+	requestURL := fmt.Sprintf("%s/accounts/%d/courses?page=1&per_page=%d&%s", c.BaseURL, accountID, c.PageSize, opts.values().Encode())
 
 	for {
 		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
@@ -4,18 +4,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 
 	"github.com/ninja-software/terror/v2"
 )
 
 type Enrollment struct {
-	ID              int    `json:"id"`
-	UserID          int    `json:"user_id"`
-	CourseID        int    `json:"course_id"`
-	CourseSectionID int    `json:"course_section_id"`
-	SISSectionID    string `json:"sis_section_id"`
-	Grades          struct {
+	ID              int            `json:"id"`
+	UserID          int            `json:"user_id"`
+	CourseID        int            `json:"course_id"`
+	CourseSectionID int            `json:"course_section_id"`
+	SISSectionID    string         `json:"sis_section_id"`
+	Type            EnrollmentType `json:"type"`
+	EnrollmentState string         `json:"enrollment_state"`
+	LastActivityAt  string         `json:"last_activity_at"`
+	// Seconds spent in the course
+	TotalActivityTime int `json:"total_activity_time"`
+	Grades            struct {
 		HtmlUrl      string  `json:"html_url"`
 		CurrentScore float32 `json:"current_score"`
 		CurrentGrade string  `json:"current_grade"`
@@ -29,10 +35,12 @@ type Enrollment struct {
 }
 
 type EnrollmentResult struct {
+	CourseID      int     `csv:"-"`
 	StudentID     string  `csv:"Student ID"`
 	StudentName   string  `csv:"Student Name"`
 	Qualification string  `csv:"Qualification"`
 	CourseName    string  `csv:"Course Name"`
+	Term          string  `csv:"Term"`
 	CourseStatus  string  `csv:"Course Status"`
 	CurrentGrade  string  `csv:"Current Grade"`
 	CurrentScore  float32 `csv:"Current Score"`
@@ -107,22 +115,34 @@ func (c *APIClient) GetEnrollmentsByUserID(userID int) ([]*Enrollment, error) {
 
 func (c *APIClient) GetAllEnrollmentsResultsByUserID(userID int) ([]*EnrollmentResult, error) {
 	results := []*EnrollmentResult{}
-	enrollments, err := c.GetEnrollmentsByUserID(userID)
+	courses := make(map[int]*Course)
+	var enrollments []*Enrollment
+	var err error
+	if c.UseGraphQL {
+		enrollments, courses, err = c.getEnrollmentsWithCoursesGraphQL(userID)
+	} else {
+		enrollments, err = c.GetEnrollmentsByUserID(userID)
+	}
 	if err != nil {
 		return nil, terror.Error(err, fmt.Sprintf("cannot get enrollments of user ID:%d", userID))
 	}
 
 	for _, enrollment := range enrollments {
-		course, err := c.GetCourseByID(enrollment.CourseID)
-		if err != nil {
-			fmt.Println(err.Error())
-			continue
+		course := courses[enrollment.CourseID]
+		if course == nil {
+			course, err = c.GetCourseByID(enrollment.CourseID)
+			if err != nil {
+				log.Println(err.Error())
+				continue
+			}
 		}
 		result := &EnrollmentResult{
+			CourseID:      course.ID,
 			StudentID:     enrollment.User.SISUserID,
 			StudentName:   enrollment.User.Name,
 			Qualification: course.Account.Name,
 			CourseName:    course.Name,
+			Term:          course.TermName(),
 			CourseStatus:  course.WorkflowState,
 			CurrentGrade:  enrollment.Grades.CurrentGrade,
 			CurrentScore:  enrollment.Grades.CurrentScore,
@@ -182,3 +202,51 @@ func (c *APIClient) GetEnrollmentsBySectionID(sectionID int, enrollmentTypes ...
 
 	return enrollments, nil
 }
+
+// enrollmentType accepted values: StudentEnrollment, TeacherEnrollment, TaEnrollment, DesignerEnrollment, and ObserverEnrollment
+func (c *APIClient) GetEnrollmentsByCourseID(courseID int, enrollmentTypes ...EnrollmentType) ([]*Enrollment, error) {
+	enrollments := []*Enrollment{}
+	requestURL := fmt.Sprintf("%s/courses/%d/enrollments?page=1&per_page=%d", c.BaseURL, courseID, c.PageSize)
+	for _, enrollmentType := range enrollmentTypes {
+		requestURL += fmt.Sprintf(`&type[]=%s`, enrollmentType)
+	}
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_enrollments := []*Enrollment{}
+		if err := json.Unmarshal(body, &_enrollments); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		enrollments = append(enrollments, _enrollments...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return enrollments, nil
+}
@@ -0,0 +1,308 @@
+package canvas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// GradeImportRow is one line of a teacher's results spreadsheet.
+// Assignment may be the assignment name or its Canvas ID.
+type GradeImportRow struct {
+	StudentSISID string `json:"student_id" csv:"Student ID"`
+	Assignment   string `json:"assignment" csv:"Assignment"`
+	Grade        string `json:"grade" csv:"Grade"`
+}
+
+type GradeChangeStatus string
+
+const (
+	NewGradeChange          GradeChangeStatus = "new"
+	ChangedGradeChange      GradeChangeStatus = "changed"
+	UnchangedGradeChange    GradeChangeStatus = "unchanged"
+	UnknownStudentChange    GradeChangeStatus = "unknown_student"
+	UnknownAssignmentChange GradeChangeStatus = "unknown_assignment"
+	// More than one row grades the same student in the same assignment
+	ConflictGradeChange GradeChangeStatus = "conflict"
+)
+
+// For Wails EnumBind
+var AllGradeChangeStatus = []struct {
+	Value  GradeChangeStatus
+	TSName string
+}{
+	{NewGradeChange, "NEW"},
+	{ChangedGradeChange, "CHANGED"},
+	{UnchangedGradeChange, "UNCHANGED"},
+	{UnknownStudentChange, "UNKNOWN_STUDENT"},
+	{UnknownAssignmentChange, "UNKNOWN_ASSIGNMENT"},
+	{ConflictGradeChange, "CONFLICT"},
+}
+
+type GradeChange struct {
+	Row           int               `json:"row" csv:"Row"`
+	StudentSISID  string            `json:"student_id" csv:"Student ID"`
+	StudentName   string            `json:"student_name" csv:"Student Name"`
+	UserID        int               `json:"user_id" csv:"-"`
+	Assignment    string            `json:"assignment" csv:"Assignment"`
+	AssignmentID  int               `json:"assignment_id" csv:"-"`
+	CurrentGrade  string            `json:"current_grade" csv:"Current Grade"`
+	NewGrade      string            `json:"new_grade" csv:"New Grade"`
+	Status        GradeChangeStatus `json:"status" csv:"Status"`
+	StatusDetails string            `json:"status_details" csv:"Details"`
+}
+
+type GradeImportPlan struct {
+	CourseID   int                       `json:"course_id"`
+	CourseName string                    `json:"course_name"`
+	Changes    []*GradeChange            `json:"changes"`
+	Summary    map[GradeChangeStatus]int `json:"summary"`
+}
+
+// Pending returns the changes that would be written to Canvas.
+func (plan *GradeImportPlan) Pending() []*GradeChange {
+	changes := []*GradeChange{}
+	for _, change := range plan.Changes {
+		if change.Status == NewGradeChange || change.Status == ChangedGradeChange {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// PreviewGradeImport resolves students and assignments of the rows and compares the grades with Canvas.
+// Nothing is written, the plan is the dry-run diff to confirm before ApplyGradeImport.
+func (c *Controller) PreviewGradeImport(courseID int, rows []*GradeImportRow) (*GradeImportPlan, error) {
+	course, err := c.APIClient.GetCourseByID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving course")
+	}
+
+	assignments, err := c.APIClient.GetAssignmentsByCourseID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving assignments")
+	}
+
+	enrollments, err := c.APIClient.GetEnrollmentsByCourseID(courseID, StudentEnrollment)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving enrollments")
+	}
+
+	submissions, err := c.APIClient.GetSubmissionsByCourseID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving submissions")
+	}
+
+	assignmentsByID := make(map[int]*Assignment)
+	assignmentsByName := make(map[string][]*Assignment)
+	for _, assignment := range assignments {
+		assignmentsByID[assignment.ID] = assignment
+		name := strings.ToLower(strings.TrimSpace(assignment.Name))
+		assignmentsByName[name] = append(assignmentsByName[name], assignment)
+	}
+
+	// Students without a SIS ID can't be matched, a blank Student ID would pick one of them
+	students := make(map[string]*Enrollment)
+	for _, enrollment := range enrollments {
+		if enrollment.User.SISUserID == "" {
+			continue
+		}
+		students[strings.ToLower(enrollment.User.SISUserID)] = enrollment
+	}
+
+	grades := make(map[string]string)
+	for _, submission := range submissions {
+		grades[fmt.Sprintf("%d-%d", submission.UserID, submission.AssignmentID)] = submission.Grade
+	}
+
+	plan := &GradeImportPlan{
+		CourseID:   course.ID,
+		CourseName: course.Name,
+		Changes:    []*GradeChange{},
+		Summary:    make(map[GradeChangeStatus]int),
+	}
+
+	for i, row := range rows {
+		change := &GradeChange{
+			Row:          i + 1,
+			StudentSISID: strings.TrimSpace(row.StudentSISID),
+			Assignment:   strings.TrimSpace(row.Assignment),
+			NewGrade:     strings.TrimSpace(row.Grade),
+		}
+		plan.Changes = append(plan.Changes, change)
+
+		if change.StudentSISID == "" {
+			change.Status = UnknownStudentChange
+			change.StatusDetails = "blank student ID"
+			continue
+		}
+
+		student := students[strings.ToLower(change.StudentSISID)]
+		if student == nil {
+			change.Status = UnknownStudentChange
+			change.StatusDetails = "no student enrollment in course"
+			continue
+		}
+		change.UserID = student.UserID
+		change.StudentName = student.User.Name
+
+		var assignment *Assignment
+		if id, err := strconv.Atoi(change.Assignment); err == nil && assignmentsByID[id] != nil {
+			assignment = assignmentsByID[id]
+		} else {
+			matches := assignmentsByName[strings.ToLower(change.Assignment)]
+			if len(matches) > 1 {
+				change.Status = UnknownAssignmentChange
+				change.StatusDetails = fmt.Sprintf("%d assignments share this name, use the assignment ID", len(matches))
+				continue
+			}
+			if len(matches) == 1 {
+				assignment = matches[0]
+			}
+		}
+		if assignment == nil {
+			change.Status = UnknownAssignmentChange
+			change.StatusDetails = "no assignment with this name or ID in course"
+			continue
+		}
+		change.AssignmentID = assignment.ID
+		change.Assignment = assignment.Name
+
+		change.CurrentGrade = grades[fmt.Sprintf("%d-%d", change.UserID, change.AssignmentID)]
+		switch {
+		case change.NewGrade == "":
+			change.Status = UnchangedGradeChange
+			change.StatusDetails = "blank grade, left as is"
+		case change.CurrentGrade == "":
+			change.Status = NewGradeChange
+		case GradesEqual(change.CurrentGrade, change.NewGrade):
+			change.Status = UnchangedGradeChange
+		default:
+			change.Status = ChangedGradeChange
+		}
+	}
+
+	// Only one grade per submission can be sent, duplicate rows are left for the user to fix
+	rowsBySubmission := make(map[string][]*GradeChange)
+	for _, change := range plan.Changes {
+		if change.AssignmentID != 0 && change.NewGrade != "" {
+			key := fmt.Sprintf("%d-%d", change.UserID, change.AssignmentID)
+			rowsBySubmission[key] = append(rowsBySubmission[key], change)
+		}
+	}
+	for _, changes := range rowsBySubmission {
+		if len(changes) < 2 {
+			continue
+		}
+
+		rows := []string{}
+		for _, change := range changes {
+			rows = append(rows, strconv.Itoa(change.Row))
+		}
+		for _, change := range changes {
+			change.Status = ConflictGradeChange
+			change.StatusDetails = fmt.Sprintf("rows %s grade the same student and assignment", strings.Join(rows, ", "))
+		}
+	}
+
+	for _, change := range plan.Changes {
+		plan.Summary[change.Status]++
+	}
+
+	return plan, nil
+}
+
+// ApplyGradeImport writes the new and changed grades of a previewed plan, one bulk update per assignment.
+// confirmed must be set by the caller after the user has accepted the diff. Grades changed in Canvas
+// since the preview are left alone and returned as conflicts.
+func (c *Controller) ApplyGradeImport(plan *GradeImportPlan, confirmed bool) ([]*GradeUpdateResult, error) {
+	if !confirmed {
+		return nil, terror.Error(fmt.Errorf("grade import of course %d not confirmed", plan.CourseID), "grade import must be confirmed before grades are changed")
+	}
+
+	client := WithOperation(c.APIClient, fmt.Sprintf("grade import into %s (%d)", plan.CourseName, plan.CourseID))
+	results := []*GradeUpdateResult{}
+	updatesByAssignment := make(map[int][]*GradeUpdate)
+	assignmentIDs := []int{}
+	for _, change := range plan.Pending() {
+		if updatesByAssignment[change.AssignmentID] == nil {
+			assignmentIDs = append(assignmentIDs, change.AssignmentID)
+		}
+		updatesByAssignment[change.AssignmentID] = append(updatesByAssignment[change.AssignmentID], &GradeUpdate{
+			UserID:      change.UserID,
+			PostedGrade: change.NewGrade,
+		})
+	}
+
+	for _, assignmentID := range assignmentIDs {
+		updates, conflicts, err := client.checkGradeImport(plan, assignmentID, updatesByAssignment[assignmentID])
+		if err != nil {
+			return results, err
+		}
+		results = append(results, conflicts...)
+
+		_results, err := client.BulkUpdateGrades(plan.CourseID, assignmentID, updates)
+		if err != nil {
+			return results, terror.Error(err, fmt.Sprintf("error updating grades of assignment ID: %d", assignmentID))
+		}
+		results = append(results, _results...)
+	}
+
+	return results, nil
+}
+
+// checkGradeImport splits the updates of an assignment into the ones whose current grade is still the
+// one in the preview and conflicts for the others.
+func (c *APIClient) checkGradeImport(plan *GradeImportPlan, assignmentID int, updates []*GradeUpdate) ([]*GradeUpdate, []*GradeUpdateResult, error) {
+	userIDs := []int{}
+	for _, update := range updates {
+		userIDs = append(userIDs, update.UserID)
+	}
+
+	submissions, err := c.GetSubmissionsByStudents(plan.CourseID, assignmentID, userIDs)
+	if err != nil {
+		return nil, nil, terror.Error(err, fmt.Sprintf("cannot read current grades of assignment ID: %d", assignmentID))
+	}
+
+	current := make(map[int]*Submission)
+	for _, submission := range submissions {
+		current[submission.UserID] = submission
+	}
+
+	previewed := make(map[int]*GradeChange)
+	for _, change := range plan.Pending() {
+		if change.AssignmentID == assignmentID {
+			previewed[change.UserID] = change
+		}
+	}
+
+	checked := []*GradeUpdate{}
+	conflicts := []*GradeUpdateResult{}
+	for _, update := range updates {
+		grade := ""
+		sisUserID := ""
+		if submission := current[update.UserID]; submission != nil {
+			grade = submission.Grade
+			sisUserID = submission.User.SISUserID
+		}
+
+		change := previewed[update.UserID]
+		if change != nil && !GradesEqual(grade, change.CurrentGrade) {
+			conflicts = append(conflicts, &GradeUpdateResult{
+				UserID:      update.UserID,
+				SISUserID:   sisUserID,
+				PostedGrade: update.PostedGrade,
+				Grade:       grade,
+				Status:      ConflictGrade,
+				Error:       fmt.Sprintf("grade changed in Canvas since the preview, was %q", change.CurrentGrade),
+			})
+			continue
+		}
+		checked = append(checked, update)
+	}
+
+	return checked, conflicts, nil
+}
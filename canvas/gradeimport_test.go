@@ -0,0 +1,164 @@
+package canvas
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+// fakeCourse answers the course, assignment, enrollment and submission requests of a grade import.
+type fakeCourse struct {
+	course      *Course
+	assignments []*Assignment
+	enrollments []*Enrollment
+	submissions []*Submission
+}
+
+func (f *fakeCourse) server() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/courses/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(f.course)
+	})
+	mux.HandleFunc("/courses/1/assignments", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(f.assignments)
+	})
+	mux.HandleFunc("/courses/1/enrollments", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(f.enrollments)
+	})
+	mux.HandleFunc("/courses/1/students/submissions", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		students := make(map[string]bool)
+		for _, id := range query["student_ids[]"] {
+			students[id] = true
+		}
+		assignments := make(map[string]bool)
+		for _, id := range query["assignment_ids[]"] {
+			assignments[id] = true
+		}
+
+		submissions := []*Submission{}
+		for _, submission := range f.submissions {
+			if !students["all"] && !students[strconv.Itoa(submission.UserID)] {
+				continue
+			}
+			if len(assignments) > 0 && !assignments[strconv.Itoa(submission.AssignmentID)] {
+				continue
+			}
+			submissions = append(submissions, submission)
+		}
+		json.NewEncoder(w).Encode(submissions)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newFakeCourse() *fakeCourse {
+	course := &fakeCourse{
+		course: &Course{ID: 1, Name: "Course"},
+		assignments: []*Assignment{
+			{ID: 10, Name: "Essay"},
+			{ID: 11, Name: "Quiz"},
+			{ID: 12, Name: "Quiz"},
+			{ID: 13, Name: "Report"},
+		},
+		submissions: []*Submission{
+			{UserID: 100, AssignmentID: 10, Grade: "B"},
+			{UserID: 101, AssignmentID: 10},
+		},
+	}
+
+	for _, student := range []struct {
+		userID int
+		sisID  string
+	}{{100, "S1"}, {101, "S2"}, {102, ""}} {
+		enrollment := &Enrollment{UserID: student.userID, Type: StudentEnrollment}
+		enrollment.User.SISUserID = student.sisID
+		course.enrollments = append(course.enrollments, enrollment)
+	}
+
+	return course
+}
+
+func TestPreviewGradeImport(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []*GradeImportRow
+		want []GradeChangeStatus
+	}{
+		{name: "new grade", rows: []*GradeImportRow{{"S2", "Essay", "A"}}, want: []GradeChangeStatus{NewGradeChange}},
+		{name: "changed grade", rows: []*GradeImportRow{{"S1", "Essay", "A"}}, want: []GradeChangeStatus{ChangedGradeChange}},
+		{name: "same grade", rows: []*GradeImportRow{{"s1", " essay ", "B"}}, want: []GradeChangeStatus{UnchangedGradeChange}},
+		{name: "blank grade", rows: []*GradeImportRow{{"S1", "Essay", ""}}, want: []GradeChangeStatus{UnchangedGradeChange}},
+		{name: "assignment ID", rows: []*GradeImportRow{{"S1", "13", "A"}}, want: []GradeChangeStatus{NewGradeChange}},
+		{name: "blank student ID", rows: []*GradeImportRow{{"", "Essay", "A"}}, want: []GradeChangeStatus{UnknownStudentChange}},
+		{name: "student not enrolled", rows: []*GradeImportRow{{"S9", "Essay", "A"}}, want: []GradeChangeStatus{UnknownStudentChange}},
+		{name: "unknown assignment", rows: []*GradeImportRow{{"S1", "Exam", "A"}}, want: []GradeChangeStatus{UnknownAssignmentChange}},
+		{name: "ambiguous assignment name", rows: []*GradeImportRow{{"S1", "Quiz", "A"}}, want: []GradeChangeStatus{UnknownAssignmentChange}},
+		{name: "ambiguous name by ID", rows: []*GradeImportRow{{"S1", "12", "A"}}, want: []GradeChangeStatus{NewGradeChange}},
+		{
+			name: "duplicate rows",
+			rows: []*GradeImportRow{{"S1", "Essay", "A"}, {"S2", "Essay", "A"}, {"s1", "10", "C"}},
+			want: []GradeChangeStatus{ConflictGradeChange, NewGradeChange, ConflictGradeChange},
+		},
+		{
+			name: "duplicate row with a blank grade",
+			rows: []*GradeImportRow{{"S1", "Essay", "A"}, {"S1", "Essay", ""}},
+			want: []GradeChangeStatus{ChangedGradeChange, UnchangedGradeChange},
+		},
+	}
+
+	fake := newFakeCourse()
+	server := fake.server()
+	defer server.Close()
+	controller := NewController(NewAPIClient(server.URL, "token", 10, server.Client(), rate.NewLimiter(rate.Inf, 1)))
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := controller.PreviewGradeImport(1, test.rows)
+			if err != nil {
+				t.Fatalf("PreviewGradeImport() error: %v", err)
+			}
+
+			got := []GradeChangeStatus{}
+			for _, change := range plan.Changes {
+				got = append(got, change.Status)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("PreviewGradeImport() statuses %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckGradeImport(t *testing.T) {
+	fake := newFakeCourse()
+	server := fake.server()
+	defer server.Close()
+	client := NewAPIClient(server.URL, "token", 10, server.Client(), rate.NewLimiter(rate.Inf, 1))
+
+	plan, err := NewController(client).PreviewGradeImport(1, []*GradeImportRow{{"S1", "Essay", "A"}, {"S2", "Essay", "A"}})
+	if err != nil {
+		t.Fatalf("PreviewGradeImport() error: %v", err)
+	}
+
+	// Someone grades S1 between the preview and the import
+	fake.submissions[0].Grade = "C"
+
+	updates := []*GradeUpdate{{UserID: 100, PostedGrade: "A"}, {UserID: 101, PostedGrade: "A"}}
+	checked, conflicts, err := client.checkGradeImport(plan, 10, updates)
+	if err != nil {
+		t.Fatalf("checkGradeImport() error: %v", err)
+	}
+
+	if len(checked) != 1 || checked[0].UserID != 101 {
+		t.Errorf("checkGradeImport() kept %v, want only user 101", checked)
+	}
+	if len(conflicts) != 1 || conflicts[0].UserID != 100 || conflicts[0].Status != ConflictGrade || conflicts[0].Grade != "C" {
+		t.Errorf("checkGradeImport() conflicts %v, want user 100 graded C", conflicts)
+	}
+}
@@ -0,0 +1,507 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type LatePolicyStatus string
+
+const (
+	LateStatus     LatePolicyStatus = "late"
+	MissingStatus  LatePolicyStatus = "missing"
+	ExtendedStatus LatePolicyStatus = "extended"
+	NoneStatus     LatePolicyStatus = "none"
+)
+
+// For Wails EnumBind
+var AllLatePolicyStatus = []struct {
+	Value  LatePolicyStatus
+	TSName string
+}{
+	{LateStatus, "LATE"},
+	{MissingStatus, "MISSING"},
+	{ExtendedStatus, "EXTENDED"},
+	{NoneStatus, "NONE"},
+}
+
+// GradeUpdate holds the changes for one student's submission, empty fields are left untouched.
+type GradeUpdate struct {
+	UserID           int              `json:"user_id"`
+	PostedGrade      string           `json:"posted_grade"`
+	Excuse           *bool            `json:"excuse"`
+	LatePolicyStatus LatePolicyStatus `json:"late_policy_status"`
+	TextComment      string           `json:"text_comment"`
+}
+
+type GradeUpdateStatus string
+
+const (
+	UpdatedGrade  GradeUpdateStatus = "updated"
+	MismatchGrade GradeUpdateStatus = "mismatch"
+	FailedGrade   GradeUpdateStatus = "failed"
+	// Not sent, the grade changed since it was previewed
+	ConflictGrade GradeUpdateStatus = "conflict"
+)
+
+type GradeUpdateResult struct {
+	UserID      int               `json:"user_id" csv:"User ID"`
+	SISUserID   string            `json:"sis_user_id" csv:"Student ID"`
+	PostedGrade string            `json:"posted_grade" csv:"Posted Grade"`
+	Grade       string            `json:"grade" csv:"Grade"`
+	Score       *float32          `json:"score" csv:"Score"`
+	Excused     bool              `json:"excused" csv:"Excused"`
+	Status      GradeUpdateStatus `json:"status" csv:"Status"`
+	Error       string            `json:"error" csv:"Error"`
+}
+
+type Progress struct {
+	ID            int     `json:"id"`
+	ContextID     int     `json:"context_id"`
+	ContextType   string  `json:"context_type"`
+	Tag           string  `json:"tag"`
+	Completion    float32 `json:"completion"`
+	WorkflowState string  `json:"workflow_state"`
+	Message       string  `json:"message"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+	URL           string  `json:"url"`
+}
+
+// How often WaitForProgress polls the Progress API
+var ProgressPollInterval = 2 * time.Second
+
+// How long WaitForProgress waits for a job that stays queued or running
+var ProgressTimeout = 10 * time.Minute
+
+var ErrProgressTimeout = errors.New("progress timed out")
+
+// UpdateSubmissionGrade grades, excuses or comments on a single student's submission.
+func (c *APIClient) UpdateSubmissionGrade(courseID int, assignmentID int, update *GradeUpdate) (*GradeUpdateResult, error) {
+	form := url.Values{}
+	if update.PostedGrade != "" {
+		form.Set("submission[posted_grade]", update.PostedGrade)
+	}
+	if update.Excuse != nil {
+		form.Set("submission[excuse]", strconv.FormatBool(*update.Excuse))
+	}
+	if update.LatePolicyStatus != "" {
+		form.Set("submission[late_policy_status]", string(update.LatePolicyStatus))
+	}
+	if update.TextComment != "" {
+		form.Set("comment[text_comment]", update.TextComment)
+	}
+
+	if err := c.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	previous, err := c.GetSubmission(courseID, assignmentID, update.UserID)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read submission before update")
+	}
+
+	submission, err := c.putSubmission(courseID, assignmentID, update.UserID, form)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.journalGrades("update submission grade", courseID, assignmentID, map[int]*Submission{update.UserID: previous}, []*Submission{submission})
+	if err != nil {
+		return nil, err
+	}
+
+	return gradeUpdateResult(update, submission), nil
+}
+
+// CommentOnSubmission adds a text comment to a student's submission without changing the grade.
+// Comments are journaled but cannot be rolled back.
+func (c *APIClient) CommentOnSubmission(courseID int, assignmentID int, userID int, text string) (*Submission, error) {
+	if err := c.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("comment[text_comment]", text)
+
+	submission, err := c.putSubmission(courseID, assignmentID, userID, form)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := c.currentOperation("comment on submission")
+	err = c.Journal.Append(&JournalEntry{
+		OperationID:  operation.ID,
+		Operation:    operation.Description,
+		Time:         time.Now().Format(time.RFC3339),
+		Kind:         CommentJournalEntry,
+		CourseID:     courseID,
+		AssignmentID: assignmentID,
+		UserID:       userID,
+		SISUserID:    submission.User.SISUserID,
+		Text:         text,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return submission, nil
+}
+
+func (c *APIClient) GetSubmission(courseID int, assignmentID int, userID int) (*Submission, error) {
+	submission := &Submission{}
+
+	requestURL := fmt.Sprintf("%s/courses/%d/assignments/%d/submissions/%d?include[]=user", c.BaseURL, courseID, assignmentID, userID)
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a get request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on get request call")
+	}
+	defer res.Body.Close()
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if err := json.Unmarshal(body, submission); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+	return submission, nil
+}
+
+func (c *APIClient) putSubmission(courseID int, assignmentID int, userID int, form url.Values) (*Submission, error) {
+	submission := &Submission{}
+
+	requestURL := fmt.Sprintf("%s/courses/%d/assignments/%d/submissions/%d?include[]=user", c.BaseURL, courseID, assignmentID, userID)
+	req, err := http.NewRequest(http.MethodPut, requestURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a put request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on put request call")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d, body: %s", res.StatusCode, body), "something went wrong and did not receive 200 OK status")
+	}
+
+	if err := json.Unmarshal(body, submission); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+
+	return submission, nil
+}
+
+// BulkUpdateGrades sends all updates of an assignment in one request, waits for Canvas to process them
+// and then reads the submissions back to report the outcome per student.
+// Canvas doesn't accept late policy statuses in bulk, those are sent one by one afterwards.
+// The requested changes are journaled before the request, so they can be rolled back whatever fails after it.
+func (c *APIClient) BulkUpdateGrades(courseID int, assignmentID int, updates []*GradeUpdate) ([]*GradeUpdateResult, error) {
+	results := []*GradeUpdateResult{}
+	if len(updates) == 0 {
+		return results, nil
+	}
+
+	if err := c.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	// The pending and the final entries belong to the same operation
+	if c.operation == nil {
+		c = WithOperation(c, "bulk update grades")
+	}
+
+	userIDs := []int{}
+	for _, update := range updates {
+		userIDs = append(userIDs, update.UserID)
+	}
+
+	before, err := c.GetSubmissionsByStudents(courseID, assignmentID, userIDs)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read submissions before update")
+	}
+
+	previous := make(map[int]*Submission)
+	for _, submission := range before {
+		previous[submission.UserID] = submission
+	}
+
+	err = c.journalPendingGrades(courseID, assignmentID, previous, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for _, update := range updates {
+		key := fmt.Sprintf("grade_data[%d]", update.UserID)
+		if update.PostedGrade != "" {
+			form.Set(key+"[posted_grade]", update.PostedGrade)
+		}
+		if update.Excuse != nil {
+			form.Set(key+"[excuse]", strconv.FormatBool(*update.Excuse))
+		}
+		if update.TextComment != "" {
+			form.Set(key+"[text_comment]", update.TextComment)
+		}
+	}
+
+	requestURL := fmt.Sprintf("%s/courses/%d/assignments/%d/submissions/update_grades", c.BaseURL, courseID, assignmentID)
+	req, err := http.NewRequest(http.MethodPost, requestURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a post request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on post request call")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d, body: %s", res.StatusCode, body), "something went wrong and did not receive 200 OK status")
+	}
+
+	// From here on the grades may have changed, whatever fails is reported after the changes are journaled
+	var updateErr error
+	progress := &Progress{}
+	if err := json.Unmarshal(body, progress); err != nil {
+		updateErr = terror.Error(err, "cannot unmarshal response body")
+	} else {
+		progress, err = c.WaitForProgress(progress.ID)
+		if err != nil {
+			updateErr = err
+		}
+	}
+
+	switch {
+	case updateErr != nil:
+	case progress.WorkflowState == "failed":
+		updateErr = terror.Error(fmt.Errorf("bulk grade update failed: %s", progress.Message), "canvas could not update grades")
+	default:
+		for _, update := range updates {
+			if update.LatePolicyStatus == "" {
+				continue
+			}
+
+			form := url.Values{}
+			form.Set("submission[late_policy_status]", string(update.LatePolicyStatus))
+			if _, err := c.putSubmission(courseID, assignmentID, update.UserID, form); err != nil {
+				updateErr = terror.Error(err, fmt.Sprintf("cannot set late policy status of user ID: %d", update.UserID))
+				break
+			}
+		}
+	}
+
+	submissions, err := c.GetSubmissionsByStudents(courseID, assignmentID, userIDs)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read back updated submissions, the journal has the requested changes")
+	}
+
+	err = c.journalGrades("bulk update grades", courseID, assignmentID, previous, submissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateErr != nil {
+		return nil, updateErr
+	}
+
+	submissionsByUser := make(map[int]*Submission)
+	for _, submission := range submissions {
+		submissionsByUser[submission.UserID] = submission
+	}
+
+	for _, update := range updates {
+		submission := submissionsByUser[update.UserID]
+		if submission == nil {
+			results = append(results, &GradeUpdateResult{
+				UserID:      update.UserID,
+				PostedGrade: update.PostedGrade,
+				Status:      FailedGrade,
+				Error:       "submission not found after update",
+			})
+			continue
+		}
+
+		results = append(results, gradeUpdateResult(update, submission))
+	}
+
+	return results, nil
+}
+
+// GetSubmissionsByStudents returns the submissions of the given students for one assignment.
+func (c *APIClient) GetSubmissionsByStudents(courseID int, assignmentID int, userIDs []int, includes ...SubmissionInclude) ([]*Submission, error) {
+	submissions := []*Submission{}
+	requestURL := fmt.Sprintf("%s/courses/%d/students/submissions?page=1&per_page=%d&assignment_ids[]=%d", c.BaseURL, courseID, c.PageSize, assignmentID) + submissionIncludes(includes)
+	for _, userID := range userIDs {
+		requestURL += fmt.Sprintf("&student_ids[]=%d", userID)
+	}
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_submissions := []*Submission{}
+		if err := json.Unmarshal(body, &_submissions); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		submissions = append(submissions, _submissions...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return submissions, nil
+}
+
+func (c *APIClient) GetProgress(progressID int) (*Progress, error) {
+	progress := &Progress{}
+
+	requestURL := fmt.Sprintf("%s/progress/%d", c.BaseURL, progressID)
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a get request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on get request call")
+	}
+	defer res.Body.Close()
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if err := json.Unmarshal(body, progress); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+	return progress, nil
+}
+
+// WaitForProgress polls until the job is completed or failed, or returns ErrProgressTimeout after ProgressTimeout.
+// The job may still finish in Canvas after a timeout.
+func (c *APIClient) WaitForProgress(progressID int) (*Progress, error) {
+	deadline := time.Now().Add(ProgressTimeout)
+	for {
+		progress, err := c.GetProgress(progressID)
+		if err != nil {
+			return nil, terror.Error(err, "error polling progress")
+		}
+
+		if progress.WorkflowState == "completed" || progress.WorkflowState == "failed" {
+			return progress, nil
+		}
+
+		if time.Now().Add(ProgressPollInterval).After(deadline) {
+			return nil, terror.Error(fmt.Errorf("%w: job %d still %s after %s", ErrProgressTimeout, progressID, progress.WorkflowState, ProgressTimeout), "canvas did not finish the job in time")
+		}
+
+		time.Sleep(ProgressPollInterval)
+	}
+}
+
+func gradeUpdateResult(update *GradeUpdate, submission *Submission) *GradeUpdateResult {
+	result := &GradeUpdateResult{
+		UserID:      update.UserID,
+		SISUserID:   submission.User.SISUserID,
+		PostedGrade: update.PostedGrade,
+		Grade:       submission.Grade,
+		Score:       submission.Score,
+		Excused:     submission.Excused,
+		Status:      UpdatedGrade,
+	}
+
+	switch {
+	case update.Excuse != nil && *update.Excuse != submission.Excused:
+		result.Status = MismatchGrade
+	case update.PostedGrade != "" && !GradesEqual(update.PostedGrade, submission.EnteredGrade) && !GradesEqual(update.PostedGrade, submission.Grade):
+		result.Status = MismatchGrade
+	}
+
+	return result
+}
+
+// GradesEqual compares grades as numbers when both parse, otherwise case-insensitively,
+// so "85" matches "85.0" and "complete" matches "Complete".
+func GradesEqual(a string, b string) bool {
+	a = strings.TrimSpace(a)
+	b = strings.TrimSpace(b)
+
+	fa, errA := strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSuffix(b, "%"), 64)
+	if errA == nil && errB == nil && strings.HasSuffix(a, "%") == strings.HasSuffix(b, "%") {
+		return fa == fb
+	}
+
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,369 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// Canvas GraphQL API: https://canvas.instructure.com/doc/api/file.graphql.html
+// Connections are paged with cursors, one query returns every section or enrollment of a course
+// instead of one REST call per section or per enrollment.
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type GraphQLUser struct {
+	ID        string `json:"_id"`
+	Name      string `json:"name"`
+	SISUserID string `json:"sisId"`
+}
+
+type GraphQLSection struct {
+	ID           string `json:"_id"`
+	Name         string `json:"name"`
+	SISSectionID string `json:"sisId"`
+}
+
+type GraphQLEnrollment struct {
+	ID      string `json:"_id"`
+	Type    string `json:"type"`
+	State   string `json:"state"`
+	Section struct {
+		ID string `json:"_id"`
+	} `json:"section"`
+	Course struct {
+		ID      string `json:"_id"`
+		Name    string `json:"name"`
+		State   string `json:"state"`
+		Account struct {
+			Name string `json:"name"`
+		} `json:"account"`
+		Term *struct {
+			ID      string `json:"_id"`
+			Name    string `json:"name"`
+			StartAt string `json:"startAt"`
+			EndAt   string `json:"endAt"`
+		} `json:"term"`
+	} `json:"course"`
+	User   GraphQLUser `json:"user"`
+	Grades struct {
+		HtmlUrl      string   `json:"htmlUrl"`
+		CurrentScore *float32 `json:"currentScore"`
+		CurrentGrade string   `json:"currentGrade"`
+		FinalScore   *float32 `json:"finalScore"`
+		FinalGrade   string   `json:"finalGrade"`
+	} `json:"grades"`
+}
+
+const graphQLSectionsQuery = `query CourseSections($courseID: ID!, $first: Int, $after: String) {
+  course(id: $courseID) {
+    sectionsConnection(first: $first, after: $after) {
+      nodes { _id name sisId }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+const graphQLEnrollmentsQuery = `query CourseEnrollments($courseID: ID!, $types: [EnrollmentType!], $first: Int, $after: String) {
+  course(id: $courseID) {
+    enrollmentsConnection(first: $first, after: $after, filter: { types: $types }) {
+      nodes {
+        _id
+        type
+        state
+        section { _id }
+        user { _id name sisId }
+        grades { htmlUrl currentScore currentGrade finalScore finalGrade }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+const graphQLUserEnrollmentsQuery = `query UserEnrollments($userID: ID!) {
+  legacyNode(_id: $userID, type: User) {
+    ... on User {
+      enrollments {
+        _id
+        type
+        state
+        section { _id }
+        course { _id name state account { name } term { _id name startAt endAt } }
+        user { _id name sisId }
+        grades { htmlUrl currentScore currentGrade finalScore finalGrade }
+      }
+    }
+  }
+}`
+
+func (c *APIClient) graphQL(query string, variables map[string]interface{}, data interface{}) error {
+	payload, err := json.Marshal(&graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return terror.Error(err, "cannot marshal graphql request")
+	}
+
+	requestURL := fmt.Sprintf("%s/api/graphql", strings.TrimSuffix(c.BaseURL, "/api/v1"))
+	req, err := http.NewRequest(http.MethodPost, requestURL, bytes.NewReader(payload))
+	if err != nil {
+		return terror.Error(err, "cannot create a post request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := c.do(req)
+	if err != nil {
+		return terror.Error(err, "error on post request call")
+	}
+	defer res.Body.Close()
+
+	if res.Status != "200 OK" {
+		return terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return terror.Error(err, "cannot read response body")
+	}
+
+	result := &graphQLResponse{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return terror.Error(err, "cannot unmarshal response body")
+	}
+
+	if len(result.Errors) > 0 {
+		messages := []string{}
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return terror.Error(fmt.Errorf("graphql: %s", strings.Join(messages, "; ")), "graphql query returned errors")
+	}
+
+	if err := json.Unmarshal(result.Data, data); err != nil {
+		return terror.Error(err, "cannot unmarshal graphql data")
+	}
+
+	return nil
+}
+
+// paginateGraphQL runs query until the connection returned by page has no next page,
+// passing the previous end cursor as $after.
+func (c *APIClient) paginateGraphQL(query string, variables map[string]interface{}, page func(data json.RawMessage) (*PageInfo, error)) error {
+	variables["first"] = c.PageSize
+
+	for {
+		data := json.RawMessage{}
+		if err := c.graphQL(query, variables, &data); err != nil {
+			return err
+		}
+
+		pageInfo, err := page(data)
+		if err != nil {
+			return terror.Error(err, "cannot unmarshal graphql page")
+		}
+
+		if pageInfo == nil || !pageInfo.HasNextPage {
+			break
+		}
+
+		variables["after"] = pageInfo.EndCursor
+	}
+
+	return nil
+}
+
+func (c *APIClient) GetCourseSectionsGraphQL(courseID int) ([]*GraphQLSection, error) {
+	sections := []*GraphQLSection{}
+	variables := map[string]interface{}{"courseID": strconv.Itoa(courseID)}
+
+	err := c.paginateGraphQL(graphQLSectionsQuery, variables, func(data json.RawMessage) (*PageInfo, error) {
+		page := struct {
+			Course struct {
+				SectionsConnection struct {
+					Nodes    []*GraphQLSection `json:"nodes"`
+					PageInfo *PageInfo         `json:"pageInfo"`
+				} `json:"sectionsConnection"`
+			} `json:"course"`
+		}{}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		sections = append(sections, page.Course.SectionsConnection.Nodes...)
+		return page.Course.SectionsConnection.PageInfo, nil
+	})
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving sections")
+	}
+
+	return sections, nil
+}
+
+// enrollmentTypes accepted values: StudentEnrollment, TeacherEnrollment, TaEnrollment, DesignerEnrollment, and ObserverEnrollment
+func (c *APIClient) GetCourseEnrollmentsGraphQL(courseID int, enrollmentTypes ...EnrollmentType) ([]*GraphQLEnrollment, error) {
+	enrollments := []*GraphQLEnrollment{}
+	variables := map[string]interface{}{"courseID": strconv.Itoa(courseID)}
+	if len(enrollmentTypes) > 0 {
+		variables["types"] = enrollmentTypes
+	}
+
+	err := c.paginateGraphQL(graphQLEnrollmentsQuery, variables, func(data json.RawMessage) (*PageInfo, error) {
+		page := struct {
+			Course struct {
+				EnrollmentsConnection struct {
+					Nodes    []*GraphQLEnrollment `json:"nodes"`
+					PageInfo *PageInfo            `json:"pageInfo"`
+				} `json:"enrollmentsConnection"`
+			} `json:"course"`
+		}{}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, page.Course.EnrollmentsConnection.Nodes...)
+		return page.Course.EnrollmentsConnection.PageInfo, nil
+	})
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving enrollments")
+	}
+
+	return enrollments, nil
+}
+
+// GetUserEnrollmentsGraphQL returns the enrollments of a user including course name and qualification.
+func (c *APIClient) GetUserEnrollmentsGraphQL(userID int) ([]*GraphQLEnrollment, error) {
+	data := struct {
+		LegacyNode *struct {
+			Enrollments []*GraphQLEnrollment `json:"enrollments"`
+		} `json:"legacyNode"`
+	}{}
+	err := c.graphQL(graphQLUserEnrollmentsQuery, map[string]interface{}{"userID": strconv.Itoa(userID)}, &data)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving enrollments")
+	}
+
+	if data.LegacyNode == nil {
+		return nil, terror.Error(fmt.Errorf("user %d not found", userID), "user not found")
+	}
+
+	return data.LegacyNode.Enrollments, nil
+}
+
+// getSectionsWithTeachersGraphQL builds the section directory of a course from two
+// paged queries rather than one enrollments and one section request per section.
+func (c *APIClient) getSectionsWithTeachersGraphQL(courseID int) (map[int]*SectionWithEnrollments, error) {
+	sections := make(map[int]*SectionWithEnrollments)
+
+	_sections, err := c.GetCourseSectionsGraphQL(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, _section := range _sections {
+		section := &SectionWithEnrollments{
+			ID:           legacyID(_section.ID),
+			SISSectionID: _section.SISSectionID,
+			Name:         _section.Name,
+			Teachers:     []*SectionTeacher{},
+		}
+		if section.SISSectionID == "" {
+			section.SISSectionID = _section.Name
+		}
+		sections[section.ID] = section
+	}
+
+	enrollments, err := c.GetCourseEnrollmentsGraphQL(courseID, TeacherEnrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, enrollment := range enrollments {
+		section := sections[legacyID(enrollment.Section.ID)]
+		if section == nil {
+			continue
+		}
+		section.Teachers = append(section.Teachers, &SectionTeacher{ID: legacyID(enrollment.User.ID), Name: enrollment.User.Name})
+	}
+
+	return sections, nil
+}
+
+func legacyID(id string) int {
+	i, err := strconv.Atoi(id)
+	if err != nil {
+		return 0
+	}
+
+	return i
+}
+
+// getEnrollmentsWithCoursesGraphQL returns the enrollments of a user along with their
+// courses keyed by course ID, so callers don't need one course request per enrollment.
+func (c *APIClient) getEnrollmentsWithCoursesGraphQL(userID int) ([]*Enrollment, map[int]*Course, error) {
+	enrollments := []*Enrollment{}
+	courses := make(map[int]*Course)
+
+	_enrollments, err := c.GetUserEnrollmentsGraphQL(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, _enrollment := range _enrollments {
+		enrollment := &Enrollment{
+			ID:              legacyID(_enrollment.ID),
+			UserID:          legacyID(_enrollment.User.ID),
+			CourseID:        legacyID(_enrollment.Course.ID),
+			CourseSectionID: legacyID(_enrollment.Section.ID),
+		}
+		enrollment.User.Name = _enrollment.User.Name
+		enrollment.User.SISUserID = _enrollment.User.SISUserID
+		enrollment.Grades.HtmlUrl = _enrollment.Grades.HtmlUrl
+		enrollment.Grades.CurrentGrade = _enrollment.Grades.CurrentGrade
+		enrollment.Grades.FinalGrade = _enrollment.Grades.FinalGrade
+		if _enrollment.Grades.CurrentScore != nil {
+			enrollment.Grades.CurrentScore = *_enrollment.Grades.CurrentScore
+		}
+		if _enrollment.Grades.FinalScore != nil {
+			enrollment.Grades.FinalScore = *_enrollment.Grades.FinalScore
+		}
+		enrollments = append(enrollments, enrollment)
+
+		course := &Course{
+			ID:            enrollment.CourseID,
+			Name:          _enrollment.Course.Name,
+			WorkflowState: _enrollment.Course.State,
+		}
+		course.Account.Name = _enrollment.Course.Account.Name
+		if term := _enrollment.Course.Term; term != nil {
+			course.EnrollmentTermID = legacyID(term.ID)
+			course.Term = &Term{
+				ID:      course.EnrollmentTermID,
+				Name:    term.Name,
+				StartAt: term.StartAt,
+				EndAt:   term.EndAt,
+			}
+		}
+		courses[course.ID] = course
+	}
+
+	return enrollments, courses, nil
+}
@@ -31,3 +31,19 @@ func UTCToPerthTime(utc string) (string, error) {
 	perthTime := t.In(perthLoc)
 	return perthTime.String(), nil
 }
+
+const (
+	PerthCampus    = "PERTH"
+	AdelaideCampus = "ADL"
+)
+
+// CampusOf tells the campus from a section name, sections without an Adelaide marker are Perth.
+func CampusOf(section string) string {
+	if strings.Contains(section, "ADL") ||
+		strings.Contains(section, "ADELAIDE") ||
+		strings.Contains(section, "Adelaide") {
+		return AdelaideCampus
+	}
+
+	return PerthCampus
+}
@@ -0,0 +1,540 @@
+package canvas
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// Every write made through APIClient is appended to the journal with the values before and after,
+// so an operation can be rolled back later. Entries are never rewritten or removed.
+
+type JournalEntryKind string
+
+const (
+	GradeJournalEntry        JournalEntryKind = "grade"
+	CommentJournalEntry      JournalEntryKind = "comment"
+	ConversationJournalEntry JournalEntryKind = "conversation"
+)
+
+type GradeState struct {
+	Grade            string `json:"grade"`
+	Excused          bool   `json:"excused"`
+	LatePolicyStatus string `json:"late_policy_status"`
+}
+
+type JournalEntry struct {
+	OperationID  string           `json:"operation_id"`
+	Operation    string           `json:"operation"`
+	Time         string           `json:"time"`
+	Kind         JournalEntryKind `json:"kind"`
+	CourseID     int              `json:"course_id,omitempty"`
+	AssignmentID int              `json:"assignment_id,omitempty"`
+	UserID       int              `json:"user_id,omitempty"`
+	SISUserID    string           `json:"sis_user_id,omitempty"`
+	Previous     *GradeState      `json:"previous,omitempty"`
+	New          *GradeState      `json:"new,omitempty"`
+	Text         string           `json:"text,omitempty"`
+	Reversible   bool             `json:"reversible"`
+	// Requested but not read back yet, a later entry of the operation for the same submission supersedes it
+	Pending bool `json:"pending,omitempty"`
+	// Set on entries written by a rollback, with the position from 1 of the entry restored in the
+	// rolled back operation
+	RollbackOf    string `json:"rollback_of,omitempty"`
+	RollbackEntry int    `json:"rollback_entry,omitempty"`
+}
+
+type JournalOperation struct {
+	ID           string `json:"id"`
+	Description  string `json:"description"`
+	Time         string `json:"time"`
+	Entries      int    `json:"entries"`
+	RolledBackBy string `json:"rolled_back_by"`
+}
+
+type Journal struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewJournal(path string) *Journal {
+	return &Journal{
+		Path: path,
+	}
+}
+
+// DefaultJournalPath keeps the journal in the user config directory so it doesn't depend on
+// the folder the app was started from.
+func DefaultJournalPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "canvas-journal.jsonl"
+	}
+
+	return filepath.Join(dir, "canvas-desktop", "journal.jsonl")
+}
+
+func (j *Journal) Append(entries ...*JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := os.MkdirAll(filepath.Dir(j.Path), 0o755)
+	if err != nil {
+		return terror.Error(err, "cannot create journal folder")
+	}
+
+	file, err := os.OpenFile(j.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return terror.Error(err, "cannot open journal")
+	}
+	defer file.Close()
+
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return terror.Error(err, "cannot marshal journal entry")
+		}
+
+		_, err = file.Write(append(line, '\n'))
+		if err != nil {
+			return terror.Error(err, "cannot write journal entry")
+		}
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return terror.Error(err, "cannot sync journal")
+	}
+
+	return nil
+}
+
+func (j *Journal) Entries() ([]*JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := []*JournalEntry{}
+
+	file, err := os.Open(j.Path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, terror.Error(err, "cannot open journal")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &JournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal journal entry")
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, terror.Error(err, "cannot read journal")
+	}
+
+	return entries, nil
+}
+
+func (j *Journal) Operations() ([]*JournalOperation, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	operations := []*JournalOperation{}
+	byID := make(map[string]*JournalOperation)
+	for _, entry := range entries {
+		operation := byID[entry.OperationID]
+		if operation == nil {
+			operation = &JournalOperation{
+				ID:          entry.OperationID,
+				Description: entry.Operation,
+				Time:        entry.Time,
+			}
+			byID[entry.OperationID] = operation
+			operations = append(operations, operation)
+		}
+		operation.Entries++
+	}
+
+	for _, entry := range entries {
+		if entry.RollbackOf != "" && byID[entry.RollbackOf] != nil {
+			byID[entry.RollbackOf].RolledBackBy = entry.OperationID
+		}
+	}
+
+	return operations, nil
+}
+
+func (c *APIClient) GetJournalOperations() ([]*JournalOperation, error) {
+	if err := c.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	return c.Journal.Operations()
+}
+
+type journalOperation struct {
+	ID          string
+	Description string
+	RollbackOf  string
+}
+
+// WithOperation returns a client whose writes are journaled as one operation, so they can be
+// rolled back together. Writes on a client without an operation get one operation each.
+func WithOperation(c *APIClient, description string) *APIClient {
+	client := *c
+	client.operation = &journalOperation{
+		ID:          newOperationID(),
+		Description: description,
+	}
+
+	return &client
+}
+
+func (c *APIClient) currentOperation(description string) *journalOperation {
+	if c.operation != nil {
+		return c.operation
+	}
+
+	return &journalOperation{
+		ID:          newOperationID(),
+		Description: description,
+	}
+}
+
+func newOperationID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), hex.EncodeToString(b))
+}
+
+// Writes are refused without a journal, they could not be undone otherwise.
+func (c *APIClient) checkJournal() error {
+	if c.Journal == nil {
+		return terror.Error(fmt.Errorf("write journal not configured"), "cannot write to Canvas without a journal")
+	}
+
+	return nil
+}
+
+func gradeState(submission *Submission) *GradeState {
+	if submission == nil {
+		return &GradeState{}
+	}
+
+	return &GradeState{
+		Grade:            submission.Grade,
+		Excused:          submission.Excused,
+		LatePolicyStatus: submission.LatePolicyStatus,
+	}
+}
+
+func (c *APIClient) journalGrades(description string, courseID int, assignmentID int, previous map[int]*Submission, updated []*Submission) error {
+	operation := c.currentOperation(description)
+	now := time.Now().Format(time.RFC3339)
+
+	entries := []*JournalEntry{}
+	for _, submission := range updated {
+		entry := gradeEntry(operation, now, courseID, assignmentID, previous[submission.UserID], submission)
+		if *entry.Previous == *entry.New {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return c.Journal.Append(entries...)
+}
+
+func gradeEntry(operation *journalOperation, now string, courseID int, assignmentID int, previous *Submission, submission *Submission) *JournalEntry {
+	return &JournalEntry{
+		OperationID:  operation.ID,
+		Operation:    operation.Description,
+		Time:         now,
+		Kind:         GradeJournalEntry,
+		CourseID:     courseID,
+		AssignmentID: assignmentID,
+		UserID:       submission.UserID,
+		SISUserID:    submission.User.SISUserID,
+		Previous:     gradeState(previous),
+		New:          gradeState(submission),
+		Reversible:   true,
+		RollbackOf:   operation.RollbackOf,
+	}
+}
+
+// journalPendingGrades records the changes of a bulk update before they are sent. Canvas applies them
+// even if the client stops or fails before reading them back, the entries let them be rolled back anyway.
+func (c *APIClient) journalPendingGrades(courseID int, assignmentID int, previous map[int]*Submission, updates []*GradeUpdate) error {
+	operation := c.currentOperation("bulk update grades")
+	now := time.Now().Format(time.RFC3339)
+
+	entries := []*JournalEntry{}
+	for _, update := range updates {
+		before := gradeState(previous[update.UserID])
+		after := *before
+		if update.PostedGrade != "" {
+			after.Grade = update.PostedGrade
+		}
+		if update.Excuse != nil {
+			after.Excused = *update.Excuse
+		}
+		if update.LatePolicyStatus != "" {
+			after.LatePolicyStatus = string(update.LatePolicyStatus)
+		}
+		if *before == after {
+			continue
+		}
+
+		sisUserID := ""
+		if previous[update.UserID] != nil {
+			sisUserID = previous[update.UserID].User.SISUserID
+		}
+
+		entries = append(entries, &JournalEntry{
+			OperationID:  operation.ID,
+			Operation:    operation.Description,
+			Time:         now,
+			Kind:         GradeJournalEntry,
+			CourseID:     courseID,
+			AssignmentID: assignmentID,
+			UserID:       update.UserID,
+			SISUserID:    sisUserID,
+			Previous:     before,
+			New:          &after,
+			Reversible:   true,
+			Pending:      true,
+			RollbackOf:   operation.RollbackOf,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return c.Journal.Append(entries...)
+}
+
+type journalRow struct {
+	courseID     int
+	assignmentID int
+	userID       int
+}
+
+func (e *JournalEntry) row() journalRow {
+	return journalRow{e.CourseID, e.AssignmentID, e.UserID}
+}
+
+type RollbackStatus string
+
+const (
+	RestoredRollback RollbackStatus = "restored"
+	ConflictRollback RollbackStatus = "conflict"
+	SkippedRollback  RollbackStatus = "skipped"
+	FailedRollback   RollbackStatus = "failed"
+)
+
+type RollbackResult struct {
+	CourseID      int            `json:"course_id" csv:"Course ID"`
+	AssignmentID  int            `json:"assignment_id" csv:"Assignment ID"`
+	UserID        int            `json:"user_id" csv:"User ID"`
+	SISUserID     string         `json:"sis_user_id" csv:"Student ID"`
+	JournalGrade  string         `json:"journal_grade" csv:"Grade Set"`
+	CurrentGrade  string         `json:"current_grade" csv:"Current Grade"`
+	RestoredGrade string         `json:"restored_grade" csv:"Restored Grade"`
+	Status        RollbackStatus `json:"status" csv:"Status"`
+	Details       string         `json:"details" csv:"Details"`
+}
+
+type RollbackReport struct {
+	OperationID         string            `json:"operation_id"`
+	RollbackOperationID string            `json:"rollback_operation_id"`
+	Results             []*RollbackResult `json:"results"`
+}
+
+// RollbackOperation restores the values from before an operation. Rows changed by someone else
+// since then are left alone and reported as conflicts. It can run again after failures or conflicts,
+// entries restored by an earlier rollback are skipped.
+func (c *APIClient) RollbackOperation(operationID string) (*RollbackReport, error) {
+	if err := c.checkJournal(); err != nil {
+		return nil, err
+	}
+
+	entries, err := c.Journal.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	operationEntries := []*JournalEntry{}
+	restored := make(map[int]string)
+	for _, entry := range entries {
+		if entry.RollbackOf == operationID && entry.RollbackEntry > 0 {
+			restored[entry.RollbackEntry-1] = entry.OperationID
+		}
+		if entry.OperationID == operationID {
+			operationEntries = append(operationEntries, entry)
+		}
+	}
+
+	if len(operationEntries) == 0 {
+		return nil, terror.Error(fmt.Errorf("operation %s not found in journal", operationID), "operation not found")
+	}
+
+	client := *c
+	client.operation = &journalOperation{
+		ID:          newOperationID(),
+		Description: fmt.Sprintf("rollback of %s", operationID),
+		RollbackOf:  operationID,
+	}
+
+	report := &RollbackReport{
+		OperationID:         operationID,
+		RollbackOperationID: client.operation.ID,
+		Results:             []*RollbackResult{},
+	}
+
+	// A pending entry is superseded when its bulk update journaled the row after it
+	resolved := make(map[journalRow]bool)
+
+	// Undo the latest change first in case a row was written more than once
+	for i := len(operationEntries) - 1; i >= 0; i-- {
+		entry := operationEntries[i]
+		if entry.Pending {
+			superseded := resolved[entry.row()]
+			resolved[entry.row()] = false
+			if superseded {
+				continue
+			}
+		} else {
+			resolved[entry.row()] = true
+		}
+
+		result := &RollbackResult{
+			CourseID:     entry.CourseID,
+			AssignmentID: entry.AssignmentID,
+			UserID:       entry.UserID,
+			SISUserID:    entry.SISUserID,
+		}
+		report.Results = append(report.Results, result)
+
+		if rollbackID := restored[i]; rollbackID != "" {
+			result.Status = SkippedRollback
+			result.Details = fmt.Sprintf("already rolled back by %s", rollbackID)
+			continue
+		}
+
+		if !entry.Reversible || entry.Kind != GradeJournalEntry {
+			result.Status = SkippedRollback
+			result.Details = fmt.Sprintf("%s entries cannot be rolled back", entry.Kind)
+			continue
+		}
+		result.JournalGrade = entry.New.Grade
+
+		current, err := client.GetSubmission(entry.CourseID, entry.AssignmentID, entry.UserID)
+		if err != nil {
+			result.Status = FailedRollback
+			result.Details = err.Error()
+			continue
+		}
+		result.CurrentGrade = current.Grade
+
+		if gradeStateMatches(current, entry.Previous) {
+			result.Status = SkippedRollback
+			result.Details = "already has the previous value"
+			if entry.Pending {
+				result.Details = "change was not applied"
+			}
+			continue
+		}
+
+		if !gradeStateMatches(current, entry.New) {
+			result.Status = ConflictRollback
+			result.Details = "changed by someone else since"
+			if current.GradedAt != "" {
+				result.Details += fmt.Sprintf(", graded at %s", current.GradedAt)
+			}
+			continue
+		}
+
+		form := url.Values{}
+		if entry.Previous.Excused != entry.New.Excused {
+			form.Set("submission[excuse]", fmt.Sprintf("%t", entry.Previous.Excused))
+		}
+		if !entry.Previous.Excused {
+			// An empty posted grade removes the grade
+			form.Set("submission[posted_grade]", entry.Previous.Grade)
+		}
+		if !latePolicyEqual(entry.Previous.LatePolicyStatus, entry.New.LatePolicyStatus) {
+			// Canvas leaves the status alone when it is empty, none clears it
+			status := entry.Previous.LatePolicyStatus
+			if status == "" {
+				status = string(NoneStatus)
+			}
+			form.Set("submission[late_policy_status]", status)
+		}
+
+		restored, err := client.putSubmission(entry.CourseID, entry.AssignmentID, entry.UserID, form)
+		if err != nil {
+			result.Status = FailedRollback
+			result.Details = err.Error()
+			continue
+		}
+
+		rollback := gradeEntry(client.operation, time.Now().Format(time.RFC3339), entry.CourseID, entry.AssignmentID, current, restored)
+		rollback.RollbackEntry = i + 1
+		err = client.Journal.Append(rollback)
+		if err != nil {
+			return report, err
+		}
+
+		result.RestoredGrade = restored.Grade
+		result.Status = RestoredRollback
+	}
+
+	return report, nil
+}
+
+// gradeStateMatches compares a submission with a journaled state. Canvas may show a posted grade
+// differently, e.g. "85%" for "85", so the entered grade counts as well.
+func gradeStateMatches(submission *Submission, state *GradeState) bool {
+	if submission.Excused != state.Excused || !latePolicyEqual(submission.LatePolicyStatus, state.LatePolicyStatus) {
+		return false
+	}
+
+	return GradesEqual(submission.Grade, state.Grade) || GradesEqual(submission.EnteredGrade, state.Grade)
+}
+
+// Canvas returns no late policy status or none for submissions without one.
+func latePolicyEqual(a string, b string) bool {
+	if a == string(NoneStatus) {
+		a = ""
+	}
+	if b == string(NoneStatus) {
+		b = ""
+	}
+
+	return a == b
+}
@@ -0,0 +1,204 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+// fakeSubmissions answers GetSubmission and putSubmission for one assignment.
+type fakeSubmissions struct {
+	mu          sync.Mutex
+	submissions map[int]*Submission
+	failing     map[int]bool
+	puts        []string
+}
+
+func (f *fakeSubmissions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var courseID, assignmentID, userID int
+	_, err := fmt.Sscanf(r.URL.Path, "/courses/%d/assignments/%d/submissions/%d", &courseID, &assignmentID, &userID)
+	submission := f.submissions[userID]
+	if err != nil || submission == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		if f.failing[userID] {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		r.ParseForm()
+		if r.Form.Has("submission[excuse]") {
+			submission.Excused = r.Form.Get("submission[excuse]") == "true"
+		}
+		if r.Form.Has("submission[posted_grade]") {
+			submission.Grade = r.Form.Get("submission[posted_grade]")
+		}
+		if r.Form.Has("submission[late_policy_status]") {
+			submission.LatePolicyStatus = r.Form.Get("submission[late_policy_status]")
+		}
+		f.puts = append(f.puts, fmt.Sprintf("%d:%s", userID, submission.Grade))
+	}
+
+	json.NewEncoder(w).Encode(submission)
+}
+
+func testSubmission(userID int, grade string) *Submission {
+	return &Submission{UserID: userID, Grade: grade}
+}
+
+func TestRollbackOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		// Grades in Canvas when the rollback runs
+		grades map[int]string
+		// Writes the operation to roll back
+		journal func(client *APIClient) error
+		// PUTs of these students fail on a first rollback, the results are of a second one
+		failFirst []int
+		want      []string
+		wantPuts  []string
+		wantAfter map[int]string
+	}{
+		{
+			name:   "restores the previous grade",
+			grades: map[int]string{1: "B"},
+			journal: func(client *APIClient) error {
+				return client.journalGrades("", 1, 1, map[int]*Submission{1: testSubmission(1, "A")}, []*Submission{testSubmission(1, "B")})
+			},
+			want:      []string{"1:restored"},
+			wantPuts:  []string{"1:A"},
+			wantAfter: map[int]string{1: "A"},
+		},
+		{
+			name:   "skips pending entries superseded by the read back",
+			grades: map[int]string{1: "B"},
+			journal: func(client *APIClient) error {
+				previous := map[int]*Submission{1: testSubmission(1, "A")}
+				err := client.journalPendingGrades(1, 1, previous, []*GradeUpdate{{UserID: 1, PostedGrade: "B"}})
+				if err != nil {
+					return err
+				}
+				return client.journalGrades("", 1, 1, previous, []*Submission{testSubmission(1, "B")})
+			},
+			want:      []string{"1:restored"},
+			wantPuts:  []string{"1:A"},
+			wantAfter: map[int]string{1: "A"},
+		},
+		{
+			name:   "pending entry never applied",
+			grades: map[int]string{1: "A"},
+			journal: func(client *APIClient) error {
+				return client.journalPendingGrades(1, 1, map[int]*Submission{1: testSubmission(1, "A")}, []*GradeUpdate{{UserID: 1, PostedGrade: "B"}})
+			},
+			want:      []string{"1:skipped"},
+			wantAfter: map[int]string{1: "A"},
+		},
+		{
+			name:   "row written twice is undone latest first",
+			grades: map[int]string{1: "C"},
+			journal: func(client *APIClient) error {
+				err := client.journalGrades("", 1, 1, map[int]*Submission{1: testSubmission(1, "A")}, []*Submission{testSubmission(1, "B")})
+				if err != nil {
+					return err
+				}
+				return client.journalGrades("", 1, 1, map[int]*Submission{1: testSubmission(1, "B")}, []*Submission{testSubmission(1, "C")})
+			},
+			want:      []string{"1:restored", "1:restored"},
+			wantPuts:  []string{"1:B", "1:A"},
+			wantAfter: map[int]string{1: "A"},
+		},
+		{
+			name:   "grade changed by someone else",
+			grades: map[int]string{1: "D"},
+			journal: func(client *APIClient) error {
+				return client.journalGrades("", 1, 1, map[int]*Submission{1: testSubmission(1, "A")}, []*Submission{testSubmission(1, "B")})
+			},
+			want:      []string{"1:conflict"},
+			wantAfter: map[int]string{1: "D"},
+		},
+		{
+			name:   "resumes a partly finished rollback",
+			grades: map[int]string{1: "B", 2: "B"},
+			journal: func(client *APIClient) error {
+				previous := map[int]*Submission{1: testSubmission(1, "A"), 2: testSubmission(2, "A")}
+				return client.journalGrades("", 1, 1, previous, []*Submission{testSubmission(1, "B"), testSubmission(2, "B")})
+			},
+			failFirst: []int{1},
+			want:      []string{"2:skipped", "1:restored"},
+			wantPuts:  []string{"2:A", "1:A"},
+			wantAfter: map[int]string{1: "A", 2: "A"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &fakeSubmissions{submissions: map[int]*Submission{}, failing: map[int]bool{}}
+			for userID, grade := range test.grades {
+				fake.submissions[userID] = testSubmission(userID, grade)
+			}
+			for _, userID := range test.failFirst {
+				fake.failing[userID] = true
+			}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			client := NewAPIClient(server.URL, "token", 10, server.Client(), rate.NewLimiter(rate.Inf, 1))
+			client.Journal = NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+			client = WithOperation(client, "test")
+			if err := test.journal(client); err != nil {
+				t.Fatalf("journal: %v", err)
+			}
+
+			report, err := client.RollbackOperation(client.operation.ID)
+			if err != nil {
+				t.Fatalf("RollbackOperation() error: %v", err)
+			}
+			if len(test.failFirst) > 0 {
+				for _, result := range report.Results {
+					if fake.failing[result.UserID] && result.Status != FailedRollback {
+						t.Fatalf("first rollback of %d is %s, want %s", result.UserID, result.Status, FailedRollback)
+					}
+				}
+				fake.failing = map[int]bool{}
+				report, err = client.RollbackOperation(client.operation.ID)
+				if err != nil {
+					t.Fatalf("second RollbackOperation() error: %v", err)
+				}
+			}
+
+			got := []string{}
+			for _, result := range report.Results {
+				got = append(got, fmt.Sprintf("%d:%s", result.UserID, result.Status))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("RollbackOperation() results %v, want %v", got, test.want)
+			}
+
+			if strings.Join(fake.puts, ",") != strings.Join(test.wantPuts, ",") {
+				t.Errorf("RollbackOperation() put %v, want %v", fake.puts, test.wantPuts)
+			}
+
+			after := map[int]string{}
+			for userID, submission := range fake.submissions {
+				after[userID] = submission.Grade
+			}
+			if !reflect.DeepEqual(after, test.wantAfter) {
+				t.Errorf("grades after rollback %v, want %v", after, test.wantAfter)
+			}
+		})
+	}
+}
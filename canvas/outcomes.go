@@ -0,0 +1,488 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type OutcomeGroup struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ParentGroup struct {
+		ID int `json:"id"`
+	} `json:"parent_outcome_group"`
+}
+
+type Outcome struct {
+	ID                int     `json:"id"`
+	Title             string  `json:"title"`
+	DisplayName       string  `json:"display_name"`
+	Description       string  `json:"description"`
+	MasteryPoints     float32 `json:"mastery_points"`
+	PointsPossible    float32 `json:"points_possible"`
+	CalculationMethod string  `json:"calculation_method"`
+	Ratings           []struct {
+		Description string  `json:"description"`
+		Points      float32 `json:"points"`
+	} `json:"ratings"`
+}
+
+// OutcomeLink places an outcome in a group of a course.
+type OutcomeLink struct {
+	OutcomeGroup *OutcomeGroup `json:"outcome_group"`
+	Outcome      *Outcome      `json:"outcome"`
+}
+
+type OutcomeResult struct {
+	ID                    int     `json:"id"`
+	Score                 float32 `json:"score"`
+	Possible              float32 `json:"possible"`
+	Mastery               bool    `json:"mastery"`
+	SubmittedOrAssessedAt string  `json:"submitted_or_assessed_at"`
+	Links                 struct {
+		User            string `json:"user"`
+		LearningOutcome string `json:"learning_outcome"`
+		Alignment       string `json:"alignment"`
+	} `json:"links"`
+}
+
+// OutcomeAlignment is the assignment or quiz an outcome was assessed in, its ID looks like "assignment_123".
+type OutcomeAlignment struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	HtmlURL string `json:"html_url"`
+}
+
+type OutcomeResults struct {
+	Results    []*OutcomeResult
+	Alignments map[string]*OutcomeAlignment
+	Outcomes   map[string]*Outcome
+}
+
+type OutcomeRollup struct {
+	Scores []struct {
+		Score       float32 `json:"score"`
+		Count       int     `json:"count"`
+		Title       string  `json:"title"`
+		SubmittedAt string  `json:"submitted_at"`
+		Links       struct {
+			Outcome string `json:"outcome"`
+		} `json:"links"`
+	} `json:"scores"`
+	Links struct {
+		User    string `json:"user"`
+		Section string `json:"section"`
+	} `json:"links"`
+}
+
+type OutcomeRollups struct {
+	Rollups  []*OutcomeRollup
+	Outcomes map[string]*Outcome
+}
+
+type MasteryStatus string
+
+const (
+	MasteredOutcome    MasteryStatus = "mastered"
+	NotMasteredOutcome MasteryStatus = "not_mastered"
+	NotAssessedOutcome MasteryStatus = "not_assessed"
+)
+
+// OutcomeMastery is a student's standing on one outcome of a course.
+type OutcomeMastery struct {
+	StudentID     string        `json:"student_id" csv:"Student ID"`
+	StudentName   string        `json:"student_name" csv:"Student Name"`
+	Qualification string        `json:"qualification" csv:"Qualification"`
+	CourseName    string        `json:"course_name" csv:"Course"`
+	OutcomeGroup  string        `json:"outcome_group" csv:"Outcome Group"`
+	Outcome       string        `json:"outcome" csv:"Outcome"`
+	MasteryPoints float32       `json:"mastery_points" csv:"Mastery Points"`
+	Score         *float32      `json:"score" csv:"Score"`
+	Status        MasteryStatus `json:"status" csv:"Status"`
+	// The latest assessment showing mastery, empty until the outcome is mastered
+	DemonstratedBy string `json:"demonstrated_by" csv:"Demonstrated By"`
+	DemonstratedAt string `json:"demonstrated_at" csv:"Demonstrated At"`
+	// The latest assessment, mastered or not
+	LastAssessedBy string `json:"last_assessed_by" csv:"Last Assessed By"`
+	LastAssessedAt string `json:"last_assessed_at" csv:"Last Assessed At"`
+}
+
+func (c *APIClient) GetOutcomeGroupsByCourseID(courseID int) ([]*OutcomeGroup, error) {
+	groups := []*OutcomeGroup{}
+	requestURL := fmt.Sprintf("%s/courses/%d/outcome_groups?page=1&per_page=%d", c.BaseURL, courseID, c.PageSize)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_groups := []*OutcomeGroup{}
+		if err := json.Unmarshal(body, &_groups); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		groups = append(groups, _groups...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return groups, nil
+}
+
+// GetOutcomeLinksByCourseID returns every outcome of the course with the group it's in.
+func (c *APIClient) GetOutcomeLinksByCourseID(courseID int) ([]*OutcomeLink, error) {
+	links := []*OutcomeLink{}
+	requestURL := fmt.Sprintf("%s/courses/%d/outcome_group_links?page=1&per_page=%d", c.BaseURL, courseID, c.PageSize)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_links := []*OutcomeLink{}
+		if err := json.Unmarshal(body, &_links); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		links = append(links, _links...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return links, nil
+}
+
+func (c *APIClient) GetOutcomeByID(id int) (*Outcome, error) {
+	outcome := &Outcome{}
+
+	requestURL := fmt.Sprintf("%s/outcomes/%d", c.BaseURL, id)
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a get request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on get request call")
+	}
+	defer res.Body.Close()
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if err := json.Unmarshal(body, outcome); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+	return outcome, nil
+}
+
+// GetOutcomeResultsByCourseID returns every assessment of an outcome, userIDs of nil means all students.
+func (c *APIClient) GetOutcomeResultsByCourseID(courseID int, userIDs []int) (*OutcomeResults, error) {
+	results := &OutcomeResults{
+		Results:    []*OutcomeResult{},
+		Alignments: make(map[string]*OutcomeAlignment),
+		Outcomes:   make(map[string]*Outcome),
+	}
+	requestURL := fmt.Sprintf("%s/courses/%d/outcome_results?page=1&per_page=%d&include[]=alignments&include[]=outcomes", c.BaseURL, courseID, c.PageSize)
+	for _, userID := range userIDs {
+		requestURL += fmt.Sprintf("&user_ids[]=%d", userID)
+	}
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		page := struct {
+			OutcomeResults []*OutcomeResult `json:"outcome_results"`
+			Linked         struct {
+				Alignments []*OutcomeAlignment `json:"alignments"`
+				Outcomes   []*Outcome          `json:"outcomes"`
+			} `json:"linked"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+
+		results.Results = append(results.Results, page.OutcomeResults...)
+		for _, alignment := range page.Linked.Alignments {
+			results.Alignments[alignment.ID] = alignment
+		}
+		for _, outcome := range page.Linked.Outcomes {
+			results.Outcomes[fmt.Sprintf("%d", outcome.ID)] = outcome
+		}
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return results, nil
+}
+
+// GetOutcomeRollupsByCourseID returns each student's aggregate score per outcome, userIDs of nil means all students.
+func (c *APIClient) GetOutcomeRollupsByCourseID(courseID int, userIDs []int) (*OutcomeRollups, error) {
+	rollups := &OutcomeRollups{
+		Rollups:  []*OutcomeRollup{},
+		Outcomes: make(map[string]*Outcome),
+	}
+	requestURL := fmt.Sprintf("%s/courses/%d/outcome_rollups?page=1&per_page=%d&include[]=outcomes", c.BaseURL, courseID, c.PageSize)
+	for _, userID := range userIDs {
+		requestURL += fmt.Sprintf("&user_ids[]=%d", userID)
+	}
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		page := struct {
+			Rollups []*OutcomeRollup `json:"rollups"`
+			Linked  struct {
+				Outcomes []*Outcome `json:"outcomes"`
+			} `json:"linked"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+
+		rollups.Rollups = append(rollups.Rollups, page.Rollups...)
+		for _, outcome := range page.Linked.Outcomes {
+			rollups.Outcomes[fmt.Sprintf("%d", outcome.ID)] = outcome
+		}
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return rollups, nil
+}
+
+// GetMasteryReportByStudent lists every outcome of the student's courses in the qualification
+// with whether it's mastered and the assessment that showed it.
+func (c *Controller) GetMasteryReportByStudent(qualification Qualification, user *User) ([]*OutcomeMastery, error) {
+	report := []*OutcomeMastery{}
+
+	enrollments, err := c.APIClient.GetEnrollmentsByUserID(user.ID)
+	if err != nil {
+		return nil, terror.Error(err, fmt.Sprintf("cannot get enrollments of user ID: %s", user.SISUserID))
+	}
+
+	enrolled := make(map[int]bool)
+	for _, enrollment := range enrollments {
+		enrolled[enrollment.CourseID] = true
+	}
+
+	courses, err := c.APIClient.GetCoursesByAccountID(qualification.AccountID, nil)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving courses")
+	}
+
+	for _, course := range courses {
+		if !enrolled[course.ID] {
+			continue
+		}
+
+		rows, err := c.getMasteryByCourse(course, user)
+		if err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("error retrieving outcomes of course ID: %d", course.ID))
+		}
+
+		for _, row := range rows {
+			row.Qualification = qualification.Name
+		}
+		report = append(report, rows...)
+	}
+
+	return report, nil
+}
+
+func (c *Controller) getMasteryByCourse(course *Course, user *User) ([]*OutcomeMastery, error) {
+	rows := []*OutcomeMastery{}
+
+	links, err := c.APIClient.GetOutcomeLinksByCourseID(course.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return rows, nil
+	}
+
+	rollups, err := c.APIClient.GetOutcomeRollupsByCourseID(course.ID, []int{user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.APIClient.GetOutcomeResultsByCourseID(course.ID, []int{user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]float32)
+	for _, rollup := range rollups.Rollups {
+		for _, score := range rollup.Scores {
+			scores[score.Links.Outcome] = score.Score
+		}
+	}
+
+	// The latest result showing mastery and the latest result of each outcome
+	demonstrated := make(map[string]*OutcomeResult)
+	latest := make(map[string]*OutcomeResult)
+	for _, result := range results.Results {
+		outcomeID := result.Links.LearningOutcome
+		if current := latest[outcomeID]; current == nil || result.SubmittedOrAssessedAt > current.SubmittedOrAssessedAt {
+			latest[outcomeID] = result
+		}
+		if !result.Mastery {
+			continue
+		}
+		if current := demonstrated[outcomeID]; current == nil || result.SubmittedOrAssessedAt > current.SubmittedOrAssessedAt {
+			demonstrated[outcomeID] = result
+		}
+	}
+
+	for _, link := range links {
+		outcomeID := fmt.Sprintf("%d", link.Outcome.ID)
+		outcome := link.Outcome
+		if linked := rollups.Outcomes[outcomeID]; linked != nil {
+			outcome = linked
+		}
+
+		row := &OutcomeMastery{
+			StudentID:     user.SISUserID,
+			StudentName:   user.Name,
+			CourseName:    course.Name,
+			Outcome:       outcome.Title,
+			MasteryPoints: outcome.MasteryPoints,
+			Status:        NotAssessedOutcome,
+		}
+		if link.OutcomeGroup != nil {
+			row.OutcomeGroup = link.OutcomeGroup.Title
+		}
+
+		if score, ok := scores[outcomeID]; ok {
+			row.Score = &score
+			row.Status = NotMasteredOutcome
+			if score >= outcome.MasteryPoints {
+				row.Status = MasteredOutcome
+			}
+		}
+
+		if result := demonstrated[outcomeID]; result != nil {
+			row.DemonstratedAt = result.SubmittedOrAssessedAt
+			if alignment := results.Alignments[result.Links.Alignment]; alignment != nil {
+				row.DemonstratedBy = alignment.Name
+			}
+		}
+		if result := latest[outcomeID]; result != nil {
+			row.LastAssessedAt = result.SubmittedOrAssessedAt
+			if alignment := results.Alignments[result.Links.Alignment]; alignment != nil {
+				row.LastAssessedBy = alignment.Name
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,140 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type QuizType string
+
+const (
+	ClassicQuiz QuizType = "classic"
+	NewQuiz     QuizType = "new"
+)
+
+// Shown as the time spent of New Quizzes, which Canvas doesn't record
+const newQuizTimeSpent = "not recorded for New Quizzes"
+
+// QuizSubmission is a student's latest attempt of a classic quiz, KeptScore follows the quiz's scoring policy.
+type QuizSubmission struct {
+	ID            int      `json:"id"`
+	QuizID        int      `json:"quiz_id"`
+	UserID        int      `json:"user_id"`
+	SubmissionID  int      `json:"submission_id"`
+	Attempt       int      `json:"attempt"`
+	Score         *float32 `json:"score"`
+	KeptScore     *float32 `json:"kept_score"`
+	TimeSpent     int      `json:"time_spent"`
+	StartedAt     string   `json:"started_at"`
+	FinishedAt    string   `json:"finished_at"`
+	WorkflowState string   `json:"workflow_state"`
+}
+
+// GetQuizSubmissionByStudent returns the latest attempt of a student at a classic quiz, or nil if they
+// never started it. Canvas only returns a single quiz submission to the student, so the request is made
+// as the student, which needs the permission to act as other users.
+func (c *APIClient) GetQuizSubmissionByStudent(courseID int, quizID int, userID int) (*QuizSubmission, error) {
+	requestURL := fmt.Sprintf("%s/courses/%d/quizzes/%d/submission?as_user_id=%d", c.BaseURL, courseID, quizID, userID)
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a get request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on get request call")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	submissions := struct {
+		QuizSubmissions []*QuizSubmission `json:"quiz_submissions"`
+	}{}
+	if err := json.Unmarshal(body, &submissions); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+
+	if len(submissions.QuizSubmissions) == 0 {
+		return nil, nil
+	}
+
+	return submissions.QuizSubmissions[0], nil
+}
+
+// addQuizResults fills the quiz columns of a student's results in one course. The student's submissions
+// tell which results are quizzes and give their attempts and kept score. Only the time spent of a classic
+// quiz needs the student's quiz submission, an error reading it is noted on the row instead of failing
+// the student. New Quizzes don't record time spent.
+func (c *APIClient) addQuizResults(courseID int, userID int, results []*AssignmentResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	submissions, err := c.GetSubmissionsByStudent(courseID, userID, AssignmentInclude)
+	if err != nil {
+		return terror.Error(err, "error retrieving submissions")
+	}
+
+	submissionsByAssignment := make(map[int]*Submission)
+	for _, submission := range submissions {
+		submissionsByAssignment[submission.AssignmentID] = submission
+	}
+
+	for _, result := range results {
+		submission := submissionsByAssignment[result.AssignmentID]
+		if submission == nil {
+			continue
+		}
+
+		switch {
+		case submission.Assignment.IsQuizAssignment && submission.Assignment.QuizID != 0:
+			result.QuizType = ClassicQuiz
+		case submission.Assignment.IsQuizLTIAssignment:
+			result.QuizType = NewQuiz
+		default:
+			continue
+		}
+		result.Attempts = submission.Attempt
+		result.KeptScore = submission.Score
+
+		if result.QuizType == NewQuiz {
+			result.TimeSpent = newQuizTimeSpent
+			continue
+		}
+		if submission.Attempt == 0 {
+			continue
+		}
+
+		quizSubmission, err := c.GetQuizSubmissionByStudent(courseID, submission.Assignment.QuizID, userID)
+		if err != nil {
+			result.QuizError = fmt.Sprintf("cannot read submission of quiz ID: %d: %s", submission.Assignment.QuizID, err)
+			continue
+		}
+		if quizSubmission == nil {
+			continue
+		}
+
+		result.Attempts = quizSubmission.Attempt
+		result.KeptScore = quizSubmission.KeptScore
+		if quizSubmission.TimeSpent > 0 {
+			result.TimeSpent = (time.Duration(quizSubmission.TimeSpent) * time.Second).String()
+		}
+	}
+
+	return nil
+}
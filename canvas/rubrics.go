@@ -0,0 +1,131 @@
+package canvas
+
+import (
+	"github.com/ninja-software/terror/v2"
+)
+
+// RubricCriterion usually maps to one performance requirement of a unit.
+type RubricCriterion struct {
+	ID              string          `json:"id"`
+	Description     string          `json:"description"`
+	LongDescription string          `json:"long_description"`
+	Points          float32         `json:"points"`
+	Ratings         []*RubricRating `json:"ratings"`
+}
+
+type RubricRating struct {
+	ID              string  `json:"id"`
+	Description     string  `json:"description"`
+	LongDescription string  `json:"long_description"`
+	Points          float32 `json:"points"`
+}
+
+type RubricAssessmentRating struct {
+	RatingID string   `json:"rating_id"`
+	Points   *float32 `json:"points"`
+	Comments string   `json:"comments"`
+}
+
+func (r *RubricAssessmentRating) assessed() bool {
+	return r != nil && (r.RatingID != "" || r.Points != nil)
+}
+
+// RubricCriterionResult is one student's rating for one criterion.
+type RubricCriterionResult struct {
+	CourseName  string   `json:"course_name" csv:"Course"`
+	StudentID   string   `json:"student_id" csv:"Student ID"`
+	StudentName string   `json:"student_name" csv:"Student Name"`
+	Assignment  string   `json:"assignment" csv:"Assignment"`
+	CriterionID string   `json:"criterion_id" csv:"-"`
+	Criterion   string   `json:"criterion" csv:"Criterion"`
+	Rating      string   `json:"rating" csv:"Rating"`
+	Points      *float32 `json:"points" csv:"Points"`
+	MaxPoints   float32  `json:"max_points" csv:"Max Points"`
+	Comments    string   `json:"comments" csv:"Comments"`
+	Assessed    bool     `json:"assessed" csv:"Assessed"`
+	// No student in the course has been rated on this criterion
+	NeverAssessed bool `json:"never_assessed" csv:"Criterion Never Assessed"`
+}
+
+// GetRubricReportByCourse lists every student's rating per rubric criterion of the course's assignments.
+// Assignments without a rubric are left out, a rubric assignment without submissions has one row per
+// criterion without a student.
+func (c *Controller) GetRubricReportByCourse(courseID int) ([]*RubricCriterionResult, error) {
+	results := []*RubricCriterionResult{}
+
+	course, err := c.APIClient.GetCourseByID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving course")
+	}
+
+	assignments, err := c.APIClient.GetAssignmentsByCourseID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving assignments")
+	}
+
+	submissions, err := c.APIClient.GetSubmissionsByCourseID(courseID, RubricAssessmentInclude)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving submissions")
+	}
+
+	submissionsByAssignment := make(map[int][]*Submission)
+	for _, submission := range submissions {
+		submissionsByAssignment[submission.AssignmentID] = append(submissionsByAssignment[submission.AssignmentID], submission)
+	}
+
+	for _, assignment := range assignments {
+		if len(assignment.Rubric) == 0 {
+			continue
+		}
+
+		rows := []*RubricCriterionResult{}
+		assessed := make(map[string]bool)
+		// Criteria are seeded so an assignment nobody submitted to still lists them as never assessed
+		if len(submissionsByAssignment[assignment.ID]) == 0 {
+			for _, criterion := range assignment.Rubric {
+				rows = append(rows, &RubricCriterionResult{
+					CourseName:  course.Name,
+					Assignment:  assignment.Name,
+					CriterionID: criterion.ID,
+					Criterion:   criterion.Description,
+					MaxPoints:   criterion.Points,
+				})
+			}
+		}
+		for _, submission := range submissionsByAssignment[assignment.ID] {
+			for _, criterion := range assignment.Rubric {
+				rating := submission.RubricAssessment[criterion.ID]
+				row := &RubricCriterionResult{
+					CourseName:  course.Name,
+					StudentID:   submission.User.SISUserID,
+					StudentName: submission.User.Name,
+					Assignment:  assignment.Name,
+					CriterionID: criterion.ID,
+					Criterion:   criterion.Description,
+					MaxPoints:   criterion.Points,
+					Assessed:    rating.assessed(),
+				}
+
+				if row.Assessed {
+					assessed[criterion.ID] = true
+					row.Points = rating.Points
+					row.Comments = rating.Comments
+					for _, r := range criterion.Ratings {
+						if r.ID == rating.RatingID {
+							row.Rating = r.Description
+						}
+					}
+				}
+
+				rows = append(rows, row)
+			}
+		}
+
+		for _, row := range rows {
+			row.NeverAssessed = !assessed[row.CriterionID]
+		}
+		results = append(results, rows...)
+	}
+
+	return results, nil
+}
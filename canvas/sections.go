@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/ninja-software/terror/v2"
 )
@@ -23,7 +24,23 @@ type SectionWithEnrollments struct {
 	ID           int
 	SISSectionID string
 	Name         string
-	Teachers     []string
+	Teachers     []*SectionTeacher
+}
+
+// SectionTeacher is the Canvas user of a teacher with the name shown in reports.
+type SectionTeacher struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TeacherNames joins the names of the teachers for the Teachers columns.
+func (s *SectionWithEnrollments) TeacherNames() string {
+	names := []string{}
+	for _, teacher := range s.Teachers {
+		names = append(names, teacher.Name)
+	}
+
+	return strings.Join(names, ";")
 }
 
 func (c *APIClient) GetSectionsByCourseID(courseID int) ([]*Section, error) {
@@ -102,3 +119,49 @@ func (c *APIClient) GetSectionByID(sectionID int) (*Section, error) {
 
 	return section, nil
 }
+
+// getSectionDirectory returns the sections of a course with their teachers, keyed by section ID.
+// Sections without a SIS ID go by their name.
+func (c *APIClient) getSectionDirectory(courseID int) (map[int]*SectionWithEnrollments, error) {
+	if c.UseGraphQL {
+		return c.getSectionsWithTeachersGraphQL(courseID)
+	}
+
+	sections, err := c.GetSectionsByCourseID(courseID)
+	if err != nil {
+		return nil, terror.Error(err, "error retreiving sections")
+	}
+
+	enrollments, err := c.GetEnrollmentsByCourseID(courseID, TeacherEnrollment)
+	if err != nil {
+		return nil, terror.Error(err, "error retreiving enrollments")
+	}
+
+	return SectionDirectory(sections, enrollments), nil
+}
+
+// SectionDirectory keys the sections by ID with the teachers of the enrollments, other enrollment types are skipped.
+func SectionDirectory(sections []*Section, enrollments []*Enrollment) map[int]*SectionWithEnrollments {
+	directory := make(map[int]*SectionWithEnrollments)
+	for _, section := range sections {
+		directory[section.ID] = &SectionWithEnrollments{
+			ID:           section.ID,
+			SISSectionID: section.SISSectionID,
+			Name:         section.Name,
+			Teachers:     []*SectionTeacher{},
+		}
+		if section.SISSectionID == "" {
+			directory[section.ID].SISSectionID = section.Name
+		}
+	}
+
+	for _, enrollment := range enrollments {
+		section := directory[enrollment.CourseSectionID]
+		if section == nil || enrollment.Type != TeacherEnrollment {
+			continue
+		}
+		section.Teachers = append(section.Teachers, &SectionTeacher{ID: enrollment.UserID, Name: enrollment.User.Name})
+	}
+
+	return directory
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
 
 	"github.com/ninja-software/terror/v2"
 )
@@ -13,23 +15,95 @@ type Submission struct {
 	ID         int    `json:"id" csv:"-"`
 	Account    string `json:"-" csv:"Qualification"`
 	CourseName string `json:"-" csv:"Course"`
+	Term       string `json:"-" csv:"Term"`
 	User       struct {
 		SISUserID string `json:"sis_user_id" csv:"ID"`
 		Name      string `json:"name" csv:"Name"`
 	} `json:"user" csv:"User"`
-	UserID          int    `json:"user_id" csv:"-"`
-	AssignmentID    int    `json:"assignment_id" csv:"-"`
-	AssignmentName  string `json:"assignment_name" csv:"Assignment Name"`
-	AssignmentDueAt string `json:"-" csv:"Due At"`
-	CourseID        int    `json:"course_id" csv:"-"`
-	Grade           string `json:"grade" csv:"Grade"`
-	SubmittedAt     string `json:"submitted_at" csv:"Submitted At"`
-	GradedAt        string `json:"graded_at" csv:"Graded At"`
-	Attempt         int    `json:"attempt" csv:"Attempt"`
-	GraderID        int    `json:"grader_id" csv:"-"`
-	Late            bool   `json:"late" csv:"Late"`
-	Excused         bool   `json:"excused" csv:"Excused"`
-	PreviewURL      string `json:"preview_url" csv:"Preview URL"`
+	UserID           int      `json:"user_id" csv:"-"`
+	AssignmentID     int      `json:"assignment_id" csv:"-"`
+	AssignmentName   string   `json:"assignment_name" csv:"Assignment Name"`
+	AssignmentDueAt  string   `json:"-" csv:"Due At"`
+	CourseID         int      `json:"course_id" csv:"-"`
+	Grade            string   `json:"grade" csv:"Grade"`
+	EnteredGrade     string   `json:"entered_grade" csv:"-"`
+	Score            *float32 `json:"score" csv:"-"`
+	SubmittedAt      string   `json:"submitted_at" csv:"Submitted At"`
+	GradedAt         string   `json:"graded_at" csv:"Graded At"`
+	Attempt          int      `json:"attempt" csv:"Attempt"`
+	GraderID         int      `json:"grader_id" csv:"-"`
+	Late             bool     `json:"late" csv:"Late"`
+	Excused          bool     `json:"excused" csv:"Excused"`
+	LatePolicyStatus string   `json:"late_policy_status" csv:"-"`
+	PreviewURL       string   `json:"preview_url" csv:"Preview URL"`
+	Resubmitted      bool     `json:"-" csv:"Resubmitted"`
+	WorkflowState    string   `json:"workflow_state" csv:"-"`
+	// False when the student submitted a new attempt after the grade was given
+	GradeMatchesCurrentSubmission bool `json:"grade_matches_current_submission" csv:"-"`
+	Assignment                    struct {
+		Name                string `json:"name"`
+		DueAt               string `json:"due_at"`
+		QuizID              int    `json:"quiz_id"`
+		IsQuizAssignment    bool   `json:"is_quiz_assignment"`
+		IsQuizLTIAssignment bool   `json:"is_quiz_lti_assignment"`
+	} `json:"assignment" csv:"-"`
+	// Only filled when fetched with SubmissionCommentsInclude
+	SubmissionComments []*SubmissionComment `json:"submission_comments" csv:"-"`
+	// Only filled when fetched with RubricAssessmentInclude, keyed by criterion ID
+	RubricAssessment map[string]*RubricAssessmentRating `json:"rubric_assessment" csv:"-"`
+}
+
+type SubmissionInclude string
+
+const (
+	SubmissionCommentsInclude SubmissionInclude = "submission_comments"
+	AssignmentInclude         SubmissionInclude = "assignment"
+	RubricAssessmentInclude   SubmissionInclude = "rubric_assessment"
+)
+
+func submissionIncludes(includes []SubmissionInclude) string {
+	query := "&include[]=user"
+	for _, include := range includes {
+		query += fmt.Sprintf("&include[]=%s", include)
+	}
+
+	return query
+}
+
+type SubmissionWorkflowState string
+
+const (
+	SubmittedSubmission     SubmissionWorkflowState = "submitted"
+	UnsubmittedSubmission   SubmissionWorkflowState = "unsubmitted"
+	GradedSubmission        SubmissionWorkflowState = "graded"
+	PendingReviewSubmission SubmissionWorkflowState = "pending_review"
+)
+
+// NeedsGrading reports whether a teacher still has to grade the submission. A resubmission
+// keeps the grade of the previous attempt, so a non-empty grade alone doesn't mean it's graded.
+func (s *Submission) NeedsGrading() bool {
+	if s.Excused {
+		return false
+	}
+
+	switch SubmissionWorkflowState(s.WorkflowState) {
+	case SubmittedSubmission, PendingReviewSubmission:
+		return true
+	case GradedSubmission:
+		return s.SubmittedAt != "" && !s.GradeMatchesCurrentSubmission
+	}
+
+	return false
+}
+
+// FillReportFields copies the course and assignment into the columns of the ungraded submissions report.
+// Assignment has to be included or filled in.
+func (s *Submission) FillReportFields(course *Course) {
+	s.CourseName = course.Name
+	s.Term = course.TermName()
+	s.AssignmentName = s.Assignment.Name
+	s.AssignmentDueAt = s.Assignment.DueAt
+	s.Resubmitted = s.Grade != "" && !s.GradeMatchesCurrentSubmission
 }
 
 func (c *APIClient) GetSubmissions(courseID int, assignmentID int) ([]Submission, error) {
@@ -72,66 +146,216 @@ func (c *APIClient) GetSubmissions(courseID int, assignmentID int) ([]Submission
 	return submissions, nil
 }
 
-func (c *APIClient) GetUngradedSubmissionsByAccount(account *Account) ([]*Submission, error) {
+// GetSubmissionsByCourseID returns the submissions of all students for all assignments of the course.
+// The user is always included.
+func (c *APIClient) GetSubmissionsByCourseID(courseID int, includes ...SubmissionInclude) ([]*Submission, error) {
+	return c.getSubmissionsByCourseID(courseID, "", includes)
+}
+
+// GetSubmissionsByCourseIDSince returns the submissions of the course submitted or graded after since,
+// an ISO 8601 time, or all of them when since is empty. The user is always included.
+func (c *APIClient) GetSubmissionsByCourseIDSince(courseID int, since string, includes ...SubmissionInclude) ([]*Submission, error) {
+	if since == "" {
+		return c.getSubmissionsByCourseID(courseID, "", includes)
+	}
+
 	submissions := []*Submission{}
-	courses, err := c.GetCoursesByAccount(account, StudenCourseEnrollment)
-	if err != nil {
-		return nil, terror.Error(err, "error retreiving courses")
+	seen := make(map[int]bool)
+	for _, param := range []string{"submitted_since", "graded_since"} {
+		_submissions, err := c.getSubmissionsByCourseID(courseID, fmt.Sprintf("&%s=%s", param, url.QueryEscape(since)), includes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, submission := range _submissions {
+			if !seen[submission.ID] {
+				seen[submission.ID] = true
+				submissions = append(submissions, submission)
+			}
+		}
 	}
 
-	for _, course := range courses[:2] {
-		assignments, err := c.GetAssignmentsByCourse(course, "ungraded")
+	return submissions, nil
+}
+
+// GetSubmissionsByStudent returns the submissions of one student in every assignment of the course.
+func (c *APIClient) GetSubmissionsByStudent(courseID int, userID int, includes ...SubmissionInclude) ([]*Submission, error) {
+	submissions := []*Submission{}
+	requestURL := fmt.Sprintf("%s/courses/%d/students/submissions?page=1&per_page=%d&student_ids[]=%d", c.BaseURL, courseID, c.PageSize, userID) + submissionIncludes(includes)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 		if err != nil {
-			return nil, terror.Error(err, "error retreiving assignments")
-		}
-		for _, assignment := range assignments {
-			requestURL := fmt.Sprintf("%s/courses/%d/assignments/%d/submissions?page=1&per_page=%d&include[]=user", c.BaseURL, course.ID, assignment.ID, c.PageSize)
-			for {
-				req, err := http.NewRequest(http.MethodGet, requestURL, nil)
-				if err != nil {
-					return nil, terror.Error(err, "cannot create http request")
-				}
-				bearer := "Bearer " + c.AccessToken
-				req.Header.Add("Authorization", bearer)
+			return nil, terror.Error(err, "cannot create http request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
 
-				res, err := c.do(req)
-				if err != nil {
-					return nil, terror.Error(err, "cannot make http call")
-				}
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "cannot make http call")
+		}
 
-				body, err := io.ReadAll(res.Body)
-				res.Body.Close()
-				if err != nil {
-					return nil, terror.Error(err, "cannot read response body")
-				}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
 
-				_submissions := []*Submission{}
-				if err := json.Unmarshal(body, &_submissions); err != nil {
-					return nil, terror.Error(err, "cannot unmarshall response body")
-				}
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
 
-				for _, submission := range _submissions {
-					if submission.Grade != "" {
-						continue
-					}
+		_submissions := []*Submission{}
+		if err := json.Unmarshal(body, &_submissions); err != nil {
+			return nil, terror.Error(err, "cannot unmarshall response body")
+		}
+		submissions = append(submissions, _submissions...)
 
-					submission.Account = account.Name
-					submission.CourseName = course.Name
-					submission.AssignmentName = assignment.Name
-					submission.AssignmentDueAt = assignment.DueAt
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
 
-					submissions = append(submissions, submission)
-				}
+		requestURL = nextURL
+	}
+
+	return submissions, nil
+}
 
-				nextURL := getNextURL(res.Header.Get("Link"))
-				if nextURL == "" {
-					break
+func (c *APIClient) getSubmissionsByCourseID(courseID int, query string, includes []SubmissionInclude) ([]*Submission, error) {
+	submissions := []*Submission{}
+	requestURL := fmt.Sprintf("%s/courses/%d/students/submissions?page=1&per_page=%d&student_ids[]=all", c.BaseURL, courseID, c.PageSize) + query + submissionIncludes(includes)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create http request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "cannot make http call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_submissions := []*Submission{}
+		if err := json.Unmarshal(body, &_submissions); err != nil {
+			return nil, terror.Error(err, "cannot unmarshall response body")
+		}
+		submissions = append(submissions, _submissions...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return submissions, nil
+}
+
+// GetUngradedSubmissionsByCourse returns the submissions of all students in the course that need grading,
+// using one paged request per workflow state instead of one per assignment.
+func (c *APIClient) GetUngradedSubmissionsByCourse(course *Course) ([]*Submission, error) {
+	submissions := []*Submission{}
+
+	for _, state := range []SubmissionWorkflowState{SubmittedSubmission, PendingReviewSubmission} {
+		requestURL := fmt.Sprintf("%s/courses/%d/students/submissions?page=1&per_page=%d&student_ids[]=all&workflow_state=%s&include[]=user&include[]=assignment", c.BaseURL, course.ID, c.PageSize, state)
+		for {
+			req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+			if err != nil {
+				return nil, terror.Error(err, "cannot create http request")
+			}
+			bearer := "Bearer " + c.AccessToken
+			req.Header.Add("Authorization", bearer)
+
+			res, err := c.do(req)
+			if err != nil {
+				return nil, terror.Error(err, "cannot make http call")
+			}
+
+			body, err := io.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				return nil, terror.Error(err, "cannot read response body")
+			}
+
+			if res.Status != "200 OK" {
+				return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+			}
+
+			_submissions := []*Submission{}
+			if err := json.Unmarshal(body, &_submissions); err != nil {
+				return nil, terror.Error(err, "cannot unmarshall response body")
+			}
+
+			for _, submission := range _submissions {
+				if !submission.NeedsGrading() {
+					continue
 				}
 
-				requestURL = nextURL
+				submission.FillReportFields(course)
+				submissions = append(submissions, submission)
 			}
-			fmt.Println("Completed - Course: ", course.Name, ", Assignment: ", assignment.Name)
+
+			nextURL := getNextURL(res.Header.Get("Link"))
+			if nextURL == "" {
+				break
+			}
+
+			requestURL = nextURL
+		}
+	}
+
+	return submissions, nil
+}
+
+// opts of nil includes courses with student enrollments
+func (c *APIClient) GetUngradedSubmissionsByAccount(account *Account, opts *CourseQueryOptions) ([]*Submission, error) {
+	submissions := []*Submission{}
+	if opts == nil {
+		opts = DefaultCourseQueryOptions()
+	}
+
+	courses, err := c.GetCoursesByAccount(account, opts)
+	if err != nil {
+		return nil, terror.Error(err, "error retreiving courses")
+	}
+
+	submissionsByCourse := make([][]*Submission, len(courses))
+	err = c.forEachCourse(courses, func(i int, course *Course) error {
+		_submissions, err := c.GetUngradedSubmissionsByCourse(course)
+		if err != nil {
+			return terror.Error(err, "error retreiving submissions")
+		}
+
+		for _, submission := range _submissions {
+			submission.Account = account.Name
 		}
+
+		submissionsByCourse[i] = _submissions
+		log.Println("Completed - Course: ", course.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, _submissions := range submissionsByCourse {
+		submissions = append(submissions, _submissions...)
 	}
 
 	return submissions, nil
@@ -0,0 +1,107 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+type Term struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	SISTermID     string `json:"sis_term_id"`
+	StartAt       string `json:"start_at"`
+	EndAt         string `json:"end_at"`
+	WorkflowState string `json:"workflow_state"`
+}
+
+// Terms only exist on the root account, sub-accounts (qualifications) share them.
+func (c *APIClient) GetTermsByAccount(account *Account) ([]*Term, error) {
+	rootAccountID := account.RootAccountID
+	if rootAccountID == 0 {
+		rootAccountID = account.ID
+	}
+
+	return c.GetTermsByAccountID(rootAccountID)
+}
+
+func (c *APIClient) GetTermsByAccountID(rootAccountID int) ([]*Term, error) {
+	terms := []*Term{}
+	requestURL := fmt.Sprintf("%s/accounts/%d/terms?page=1&per_page=%d", c.BaseURL, rootAccountID, c.PageSize)
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_terms := struct {
+			EnrollmentTerms []*Term `json:"enrollment_terms"`
+		}{}
+		if err := json.Unmarshal(body, &_terms); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		terms = append(terms, _terms.EnrollmentTerms...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return terms, nil
+}
+
+func (c *APIClient) GetTermByID(rootAccountID int, termID int) (*Term, error) {
+	term := &Term{}
+
+	requestURL := fmt.Sprintf("%s/accounts/%d/terms/%d", c.BaseURL, rootAccountID, termID)
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, terror.Error(err, "cannot create a get request")
+	}
+	bearer := "Bearer " + c.AccessToken
+	req.Header.Add("Authorization", bearer)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, terror.Error(err, "error on get request call")
+	}
+	defer res.Body.Close()
+
+	if res.Status != "200 OK" {
+		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read response body")
+	}
+
+	if err := json.Unmarshal(body, term); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal response body")
+	}
+	return term, nil
+}
@@ -2,23 +2,98 @@ package canvas
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/ninja-software/terror/v2"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type User struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	SISUserID string `json:"sis_user_id"`
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	SortableName string `json:"sortable_name"`
+	SISUserID    string `json:"sis_user_id"`
+	LoginID      string `json:"login_id"`
+	Email        string `json:"email"`
+	CreatedAt    string `json:"created_at"`
+	LastLogin    string `json:"last_login"`
+}
+
+type UserIdentifierType string
+
+const (
+	SISUserIdentifier UserIdentifierType = "sis_user_id"
+	LoginIdentifier   UserIdentifierType = "login_id"
+	EmailIdentifier   UserIdentifierType = "email"
+	// Tries SIS user ID, then login ID, then email
+	AutoIdentifier UserIdentifierType = "auto"
+)
+
+// For Wails EnumBind
+var AllUserIdentifierType = []struct {
+	Value  UserIdentifierType
+	TSName string
+}{
+	{SISUserIdentifier, "SIS_USER_ID"},
+	{LoginIdentifier, "LOGIN_ID"},
+	{EmailIdentifier, "EMAIL"},
+	{AutoIdentifier, "AUTO"},
+}
+
+type ResolvedUser struct {
+	Identifier string `json:"identifier"`
+	User       *User  `json:"user"`
+}
+
+type UnresolvedUser struct {
+	Identifier string `json:"identifier" csv:"Identifier"`
+	Reason     string `json:"reason" csv:"Reason"`
+}
+
+type UserResolution struct {
+	Users    []*ResolvedUser   `json:"users"`
+	NotFound []*UnresolvedUser `json:"not_found"`
 }
 
 func (c *APIClient) GetUserBySisID(sisID string) (*User, error) {
+	return c.getUser(fmt.Sprintf("sis_user_id:%s", url.PathEscape(sisID)), fmt.Sprintf("SIS ID %s", sisID))
+}
+
+func (c *APIClient) GetUserByLoginID(loginID string) (*User, error) {
+	return c.getUser(fmt.Sprintf("sis_login_id:%s", url.PathEscape(loginID)), fmt.Sprintf("login %s", loginID))
+}
+
+// GetUserByEmail searches the account for a user whose email or login matches exactly.
+func (c *APIClient) GetUserByEmail(accountID int, email string) (*User, error) {
+	users, err := c.SearchUsersByAccountID(accountID, email)
+	if err != nil {
+		return nil, terror.Error(err, "error searching users")
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) || strings.EqualFold(user.LoginID, email) {
+			return user, nil
+		}
+	}
+
+	return nil, terror.Error(fmt.Errorf("%w: email %s", ErrUserNotFound, email), fmt.Sprintf("no user found with email %s", email))
+}
+
+// GetSelf returns the user the access token belongs to.
+func (c *APIClient) GetSelf() (*User, error) {
+	return c.getUser("self", "the access token")
+}
+
+func (c *APIClient) getUser(id string, description string) (*User, error) {
 	user := &User{}
 
-	requestURL := fmt.Sprintf("%s/users/sis_user_id:%s", c.BaseURL, sisID)
+	requestURL := fmt.Sprintf("%s/users/%s?include[]=last_login", c.BaseURL, id)
 	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, terror.Error(err, "cannot create a get request")
@@ -32,6 +107,10 @@ func (c *APIClient) GetUserBySisID(sisID string) (*User, error) {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil, terror.Error(fmt.Errorf("%w: %s", ErrUserNotFound, description), fmt.Sprintf("no user found with %s", description))
+	}
+
 	if res.Status != "200 OK" {
 		return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
 	}
@@ -47,3 +126,125 @@ func (c *APIClient) GetUserBySisID(sisID string) (*User, error) {
 	return user, nil
 
 }
+
+// searchTerm matches name, login, email or SIS ID and needs at least 2 characters
+func (c *APIClient) SearchUsersByAccountID(accountID int, searchTerm string) ([]*User, error) {
+	users := []*User{}
+	requestURL := fmt.Sprintf("%s/accounts/%d/users?page=1&per_page=%d&search_term=%s&include[]=email&include[]=last_login", c.BaseURL, accountID, c.PageSize, url.QueryEscape(searchTerm))
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, terror.Error(err, "cannot create a get request")
+		}
+		bearer := "Bearer " + c.AccessToken
+		req.Header.Add("Authorization", bearer)
+
+		res, err := c.do(req)
+		if err != nil {
+			return nil, terror.Error(err, "error on get request call")
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot read response body")
+		}
+
+		if res.Status != "200 OK" {
+			return nil, terror.Error(fmt.Errorf("status code: %d", res.StatusCode), "something went wrong and did not receive 200 OK status")
+		}
+
+		_users := []*User{}
+		if err := json.Unmarshal(body, &_users); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal response body")
+		}
+		users = append(users, _users...)
+
+		nextURL := getNextURL(res.Header.Get("Link"))
+		if nextURL == "" {
+			break
+		}
+
+		requestURL = nextURL
+	}
+
+	return users, nil
+}
+
+// ResolveUsers looks up every identifier, Concurrency at a time, and reports the ones without a user
+// instead of failing. accountID is the account searched for emails, email lookups need one and auto
+// lookups skip emails without it.
+func (c *APIClient) ResolveUsers(accountID int, identifierType UserIdentifierType, identifiers []string) (*UserResolution, error) {
+	if identifierType == EmailIdentifier && accountID == 0 {
+		return nil, terror.Error(fmt.Errorf("no account for email lookups"), "an account is required to look users up by email")
+	}
+
+	resolution := &UserResolution{
+		Users:    []*ResolvedUser{},
+		NotFound: []*UnresolvedUser{},
+	}
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, identifier := range identifiers {
+		identifier = strings.TrimSpace(identifier)
+		if identifier == "" || seen[identifier] {
+			continue
+		}
+		seen[identifier] = true
+		unique = append(unique, identifier)
+	}
+
+	users := make([]*User, len(unique))
+	errs := make([]error, len(unique))
+	err := c.forEach(len(unique), func(i int) error {
+		users[i], errs[i] = c.resolveUser(accountID, identifierType, unique[i])
+		if errs[i] != nil && !errors.Is(errs[i], ErrUserNotFound) {
+			return terror.Error(errs[i], fmt.Sprintf("error resolving user %s", unique[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, identifier := range unique {
+		if errs[i] != nil {
+			resolution.NotFound = append(resolution.NotFound, &UnresolvedUser{
+				Identifier: identifier,
+				Reason:     errs[i].Error(),
+			})
+			continue
+		}
+
+		resolution.Users = append(resolution.Users, &ResolvedUser{
+			Identifier: identifier,
+			User:       users[i],
+		})
+	}
+
+	return resolution, nil
+}
+
+func (c *APIClient) resolveUser(accountID int, identifierType UserIdentifierType, identifier string) (*User, error) {
+	switch identifierType {
+	case SISUserIdentifier:
+		return c.GetUserBySisID(identifier)
+	case LoginIdentifier:
+		return c.GetUserByLoginID(identifier)
+	case EmailIdentifier:
+		return c.GetUserByEmail(accountID, identifier)
+	case AutoIdentifier:
+		user, err := c.GetUserBySisID(identifier)
+		if !errors.Is(err, ErrUserNotFound) {
+			return user, err
+		}
+		user, err = c.GetUserByLoginID(identifier)
+		if !errors.Is(err, ErrUserNotFound) || !strings.Contains(identifier, "@") || accountID == 0 {
+			return user, err
+		}
+		return c.GetUserByEmail(accountID, identifier)
+	}
+
+	return nil, terror.Error(fmt.Errorf("unknown identifier type: %s", identifierType), "unknown identifier type")
+}
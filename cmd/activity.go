@@ -0,0 +1,118 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+)
+
+// inactiveStudents exports the students of the accounts who haven't been active lately or spent too little time.
+func inactiveStudents(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("inactive-students", flag.ExitOnError)
+	accounts := accountFlags(flags)
+	courses := courseFlags(flags)
+	days := flags.Int("days", 14, "list students with no activity in this many days, 0 to ignore")
+	minutes := flags.Int("minutes", 0, "list students with less time in the course than this, 0 to ignore")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+	out.Accounts = accountIDs
+
+	opts, err := courses()
+	if err != nil {
+		return err
+	}
+
+	controller := canvas.NewController(client)
+	all := []*canvas.InactiveStudent{}
+	for _, accountID := range accountIDs {
+		students, err := controller.GetInactiveStudentsByAccountID(accountID, opts, canvas.InactivityCriteria{
+			InactiveDays:       *days,
+			MinActivityMinutes: *minutes,
+		})
+		if err != nil {
+			return err
+		}
+		all = append(all, students...)
+
+		if out.json() {
+			continue
+		}
+
+		err = out.write(flags, students, exports{
+			"csv": func() error {
+				return csv.ExportInactiveStudents(students, accountID)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if out.json() {
+		if err := out.write(flags, all, nil); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out.console(), "Exported %d inactive students\n", len(all))
+	return nil
+}
+
+// studentEngagement exports page views, participations and tardiness per student per course of the accounts.
+func studentEngagement(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("engagement", flag.ExitOnError)
+	accounts := accountFlags(flags)
+	courses := courseFlags(flags)
+	withActivity := flags.Bool("activity", false, "add active days and last activity, one more request per student")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+	out.Accounts = accountIDs
+
+	opts, err := courses()
+	if err != nil {
+		return err
+	}
+
+	controller := canvas.NewController(client)
+	all := []*canvas.StudentEngagement{}
+	for _, accountID := range accountIDs {
+		engagement, err := controller.GetStudentEngagementByAccountID(accountID, opts, *withActivity)
+		if err != nil {
+			return err
+		}
+		all = append(all, engagement...)
+
+		if out.json() {
+			continue
+		}
+
+		err = out.write(flags, engagement, exports{
+			"csv": func() error {
+				return csv.ExportStudentEngagement(engagement, accountID)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if out.json() {
+		if err := out.write(flags, all, nil); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out.console(), "Exported engagement of %d enrolments\n", len(all))
+	return nil
+}
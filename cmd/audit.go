@@ -0,0 +1,54 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+)
+
+// gradeAudit exports who changed which grades and when, for one assignment, course, student or grader.
+func gradeAudit(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("grade-audit", flag.ExitOnError)
+	assignmentID := flags.Int("assignment", 0, "Canvas assignment ID")
+	courseID := flags.Int("course", 0, "Canvas course ID")
+	studentID := flags.Int("student", 0, "Canvas user ID of the student")
+	graderID := flags.Int("grader", 0, "Canvas user ID of the grader")
+	from := flags.String("from", "", "only changes after this time, e.g. 2024-01-31T00:00:00+08:00")
+	to := flags.String("to", "", "only changes before this time")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	var scope canvas.GradeChangeScope
+	var id int
+	switch {
+	case *assignmentID != 0:
+		scope, id = canvas.AssignmentGradeChanges, *assignmentID
+	case *courseID != 0:
+		scope, id = canvas.CourseGradeChanges, *courseID
+	case *studentID != 0:
+		scope, id = canvas.StudentGradeChanges, *studentID
+	case *graderID != 0:
+		scope, id = canvas.GraderGradeChanges, *graderID
+	default:
+		return usageError(flags, "one of -assignment, -course, -student or -grader is required")
+	}
+
+	controller := canvas.NewController(client)
+	records, err := controller.GetGradeChangeAudit(scope, id, *from, *to)
+	if err != nil {
+		return err
+	}
+
+	err = out.write(flags, records, exports{
+		"csv": func() error {
+			return csv.ExportGradeChangeAudit(records, fmt.Sprintf("%s-%d", scope, id))
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out.console(), "Exported %d grade changes\n", len(records))
+	return nil
+}
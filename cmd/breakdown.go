@@ -0,0 +1,46 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+)
+
+// gradeBreakdown recomputes the grades of a course and reports the students whose total differs from Canvas.
+func gradeBreakdown(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("grade-breakdown", flag.ExitOnError)
+	courseID := flags.Int("course", 0, "Canvas course ID")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	if *courseID == 0 {
+		return usageError(flags, "-course is required")
+	}
+
+	controller := canvas.NewController(client)
+	breakdowns, err := controller.GetGradeBreakdownsByCourse(*courseID)
+	if err != nil {
+		return err
+	}
+
+	err = out.write(flags, breakdowns, exports{
+		"csv": func() error {
+			return csv.ExportGradeBreakdowns(breakdowns, *courseID)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	mismatches := 0
+	for _, breakdown := range breakdowns {
+		if breakdown.Mismatch {
+			mismatches++
+			fmt.Fprintf(out.console(), "Mismatch: %s %s - %s\n", breakdown.StudentID, breakdown.StudentName, breakdown.MismatchDetails)
+		}
+	}
+	fmt.Fprintf(out.console(), "Recomputed %d students, %d differ from Canvas\n", len(breakdowns), mismatches)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"canvas-desktop/email"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func loadEmailProfile(name string) (*email.Profile, error) {
+	config, err := email.LoadConfig(getenv("CANVAS_EMAIL_CONFIG", email.DefaultConfigPath()))
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Profile(name)
+}
+
+func printDeliveries(deliveries []*email.Delivery) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEMAIL\tATTACHMENTS\tSENT\tERROR")
+	for _, delivery := range deliveries {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%t\t%s\n", delivery.Name, delivery.Email, delivery.Attachments, delivery.Sent, delivery.Error)
+	}
+	w.Flush()
+}
+
+// emailTest sends a test message to every recipient of a profile to check the SMTP settings.
+func emailTest(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("email-test", flag.ExitOnError)
+	profileName := flags.String("profile", "", "profile in the email config")
+	flags.Parse(args)
+
+	if *profileName == "" {
+		return usageError(flags, "-profile is required")
+	}
+
+	profile, err := loadEmailProfile(*profileName)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := email.SendTest(profile)
+	if err != nil {
+		return err
+	}
+
+	printDeliveries(deliveries)
+	return nil
+}
+
+// emailAssignmentsStatus exports the ungraded assignments status of the accounts per campus and emails each recipient
+// their campus files.
+func emailAssignmentsStatus(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("email-assignments-status", flag.ExitOnError)
+	profileName := flags.String("profile", "", "profile in the email config")
+	accounts := accountFlags(flags)
+	courses := courseFlags(flags)
+	flags.Parse(args)
+
+	if *profileName == "" {
+		return usageError(flags, "-profile is required")
+	}
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+
+	opts, err := courses()
+	if err != nil {
+		return err
+	}
+
+	profile, err := loadEmailProfile(*profileName)
+	if err != nil {
+		return err
+	}
+
+	reports := source(client)
+	for _, accountID := range accountIDs {
+		account, err := reports.GetAccountByID(accountID)
+		if err != nil {
+			return err
+		}
+
+		assignments, err := reports.GetAssignmentsByAccount(account, canvas.UngradedBucket, opts)
+		if err != nil {
+			return err
+		}
+
+		paths, err := csv.ExportAssignmentsStatus(assignments, account)
+		if err != nil {
+			return err
+		}
+
+		attachments := []*email.Attachment{}
+		for _, campus := range []string{canvas.PerthCampus, canvas.AdelaideCampus} {
+			attachments = append(attachments, &email.Attachment{Path: paths[campus], Segment: campus})
+		}
+
+		deliveries, err := email.SendReports(profile, attachments, map[string]interface{}{
+			"Account":     account.Name,
+			"Assignments": len(assignments),
+		})
+		if err != nil {
+			return err
+		}
+
+		printDeliveries(deliveries)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+)
+
+// exportFeedback exports assessor comments of a course or of every course in the accounts.
+func exportFeedback(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("export-feedback", flag.ExitOnError)
+	courseID := flags.Int("course", 0, "Canvas course ID, instead of -account or -qualification")
+	accounts := accountFlags(flags)
+	courses := courseFlags(flags)
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	controller := canvas.NewController(client)
+	if *courseID != 0 {
+		feedback, err := controller.GetFeedbackByCourse(*courseID)
+		if err != nil {
+			return err
+		}
+		return out.write(flags, feedback, exports{
+			"csv": func() error {
+				return csv.ExportFeedback(feedback, fmt.Sprintf("%d", *courseID))
+			},
+		})
+	}
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+	out.Accounts = accountIDs
+
+	opts, err := courses()
+	if err != nil {
+		return err
+	}
+
+	all := []*canvas.SubmissionFeedback{}
+	for _, accountID := range accountIDs {
+		qualification, err := qualificationOf(client, accountID)
+		if err != nil {
+			return err
+		}
+
+		feedback, err := controller.GetFeedbackByQualification(qualification, opts)
+		if err != nil {
+			return err
+		}
+
+		if out.json() {
+			all = append(all, feedback...)
+			continue
+		}
+
+		err = out.write(flags, feedback, exports{
+			"csv": func() error {
+				return csv.ExportFeedback(feedback, qualification.Name)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if out.json() {
+		return out.write(flags, all, nil)
+	}
+	return nil
+}
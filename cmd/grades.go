@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// importGrades previews a grade CSV against Canvas and applies it once the user confirms.
+func importGrades(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("import-grades", flag.ExitOnError)
+	courseID := flags.Int("course", 0, "Canvas course ID the grades belong to")
+	filename := flags.String("file", "", "CSV file with Student ID, Assignment and Grade columns")
+	yes := flags.Bool("yes", false, "apply without asking for confirmation")
+	flags.Parse(args)
+
+	if *courseID == 0 || *filename == "" {
+		return usageError(flags, "-course and -file are required")
+	}
+
+	rows, err := csv.ReadGradeImport(*filename)
+	if err != nil {
+		return err
+	}
+
+	controller := canvas.NewController(client)
+	plan, err := controller.PreviewGradeImport(*courseID, rows)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tSTUDENT\tASSIGNMENT\tCURRENT\tNEW\tSTATUS\tDETAILS")
+	for _, change := range plan.Changes {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", change.Row, change.StudentSISID, change.Assignment, change.CurrentGrade, change.NewGrade, change.Status, change.StatusDetails)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%s: %d new, %d changed, %d unchanged, %d unknown student, %d unknown assignment, %d conflict\n",
+		plan.CourseName,
+		plan.Summary[canvas.NewGradeChange],
+		plan.Summary[canvas.ChangedGradeChange],
+		plan.Summary[canvas.UnchangedGradeChange],
+		plan.Summary[canvas.UnknownStudentChange],
+		plan.Summary[canvas.UnknownAssignmentChange],
+		plan.Summary[canvas.ConflictGradeChange],
+	)
+
+	pending := len(plan.Pending())
+	if pending == 0 {
+		fmt.Println("Nothing to apply")
+		return nil
+	}
+
+	confirmed := *yes
+	if !confirmed {
+		fmt.Printf("Apply %d grade changes to Canvas? [y/N] ", pending)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		confirmed = answer == "y" || answer == "yes"
+	}
+
+	if !confirmed {
+		fmt.Println("Cancelled, no grades were changed")
+		return nil
+	}
+
+	results, err := controller.ApplyGradeImport(plan, true)
+	if err != nil {
+		return err
+	}
+
+	err = csv.ExportGradeUpdateResults(results, plan.CourseID)
+	if err != nil {
+		return err
+	}
+
+	mismatches := 0
+	for _, result := range results {
+		if result.Status != canvas.UpdatedGrade {
+			mismatches++
+		}
+	}
+	fmt.Printf("Applied %d grade changes, %d need checking\n", len(results)-mismatches, mismatches)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// listJournal prints the journaled operations, newest last.
+func listJournal(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("journal", flag.ExitOnError)
+	flags.Parse(args)
+
+	operations, err := client.GetJournalOperations()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tTIME\tENTRIES\tDESCRIPTION\tROLLED BACK BY")
+	for _, operation := range operations {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", operation.ID, operation.Time, operation.Entries, operation.Description, operation.RolledBackBy)
+	}
+	w.Flush()
+
+	fmt.Printf("\nJournal: %s\n", client.Journal.Path)
+	return nil
+}
+
+// rollback restores the values from before a journaled operation and exports what happened per row.
+func rollback(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("rollback", flag.ExitOnError)
+	operationID := flags.String("operation", "", "operation ID from the journal command")
+	yes := flags.Bool("yes", false, "roll back without asking for confirmation")
+	flags.Parse(args)
+
+	if *operationID == "" {
+		return usageError(flags, "-operation is required")
+	}
+
+	confirmed := *yes
+	if !confirmed {
+		fmt.Printf("Roll back operation %s in Canvas? [y/N] ", *operationID)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		confirmed = answer == "y" || answer == "yes"
+	}
+
+	if !confirmed {
+		fmt.Println("Cancelled, nothing was rolled back")
+		return nil
+	}
+
+	report, err := client.RollbackOperation(*operationID)
+	if err != nil {
+		return err
+	}
+
+	err = csv.ExportRollbackReport(report)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STUDENT\tASSIGNMENT\tGRADE SET\tCURRENT\tRESTORED\tSTATUS\tDETAILS")
+	summary := make(map[canvas.RollbackStatus]int)
+	for _, result := range report.Results {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", result.SISUserID, result.AssignmentID, result.JournalGrade, result.CurrentGrade, result.RestoredGrade, result.Status, result.Details)
+		summary[result.Status]++
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d restored, %d conflicts, %d skipped, %d failed (rollback operation %s)\n",
+		summary[canvas.RestoredRollback],
+		summary[canvas.ConflictRollback],
+		summary[canvas.SkippedRollback],
+		summary[canvas.FailedRollback],
+		report.RollbackOperationID,
+	)
+
+	return nil
+}
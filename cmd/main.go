@@ -2,75 +2,127 @@ package main
 
 import (
 	"canvas-desktop/canvas"
-	"canvas-desktop/csv"
+	"canvas-desktop/mirror"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
-	"strconv"
-	"time"
-
-	"golang.org/x/time/rate"
 )
 
-func main() {
+type command struct {
+	name        string
+	description string
+	run         func(*canvas.APIClient, []string) error
+	// Commands that never call Canvas run without an access token
+	offline bool
+	// Commands that can read the local mirror instead of Canvas with -offline
+	mirrored bool
+	// Rejects the flags of a mirrored command the mirror can't answer, before anything runs
+	offlineArgs func([]string) error
+}
 
-	baseURL := getenv("CANVAS_BASE_URL", "https://skillsaustralia.instructure.com/api/v1")
-	accessToken := getenv("CANVAS_ACCESS_TOKEN", "")
-	pageSizeStr := getenv("CANVAS_PAGE_SIZE", "100")
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil {
-		panic(err.Error)
-	}
+// mirrorPath is the SQLite file of mirror-sync and -offline.
+var mirrorPath string
+
+var commands = []*command{
+	{name: "ungraded-assignments", description: "assignment status per section of an account, split by campus", run: ungradedAssignments, mirrored: true, offlineArgs: ungradedAssignmentsOffline},
+	{name: "ungraded-submissions", description: "submissions waiting to be graded in an account", run: ungradedSubmissions, mirrored: true},
+	{name: "student-results", description: "a student's result in every assignment", run: studentResults},
+	{name: "enrollment-results", description: "a student's grade in every course", run: enrollmentResults},
+	{name: "roster-results", description: "results of every student in a class list CSV", run: rosterResults},
+	{name: "qualifications", description: "list qualifications and their account IDs", run: listQualifications, offline: true},
+	{name: "whoami", description: "show the user of the access token", run: whoami},
+	{name: "import-grades", description: "preview and apply a grade CSV to a course", run: importGrades},
+	{name: "export-feedback", description: "submission comments of a course or of accounts", run: exportFeedback},
+	{name: "rubric-report", description: "rubric criterion results of a course", run: rubricReport},
+	{name: "grade-breakdown", description: "assignment group subtotals per student", run: gradeBreakdown},
+	{name: "grade-audit", description: "grade change log of a course, assignment or student", run: gradeAudit},
+	{name: "mastery-report", description: "outcome mastery of a student in the accounts", run: masteryReport},
+	{name: "inactive-students", description: "students without recent activity in an account", run: inactiveStudents},
+	{name: "engagement", description: "page views, participations and tardiness per student", run: studentEngagement},
+	{name: "nudge-teachers", description: "message teachers about their ungraded submissions", run: nudgeTeachers},
+	{name: "email-test", description: "send a test email with a profile", run: emailTest, offline: true},
+	{name: "email-assignments-status", description: "email the campus assignment status files", run: emailAssignmentsStatus, mirrored: true},
+	{name: "schedule", description: "run the reports of a schedule config on their cron expressions", run: runSchedule},
+	{name: "journal", description: "list journaled write operations", run: listJournal, offline: true},
+	{name: "rollback", description: "undo a journaled operation", run: rollback},
+	{name: "mirror-sync", description: "copy accounts into the local mirror, only the changes after the first sync", run: mirrorSync},
+	{name: "mirror-status", description: "list the mirrored accounts and when they were synced", run: mirrorStatus, offline: true},
+}
 
-	if accessToken == "" {
-		panic("missing access token")
+func main() {
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "Usage: %s [-profile name] [-concurrency n] [-offline] <command> [flags]\n\nCommands:\n", os.Args[0])
+		for _, command := range commands {
+			fmt.Fprintf(out, "  %-26s %s\n", command.name, command.description)
+		}
+		fmt.Fprintf(out, "\nRun a command with -h for its flags. Global flags:\n")
+		flag.PrintDefaults()
+	}
+	profileName := flag.String("profile", os.Getenv("CANVAS_PROFILE"), "Canvas profile from profiles.json, the CANVAS_* environment variables by default")
+	concurrency := flag.Int("concurrency", 1, "courses or students fetched at once")
+	flag.StringVar(&mirrorPath, "mirror", getenv("CANVAS_MIRROR", mirror.DefaultPath()), "SQLite file of the local mirror")
+	offline := flag.Bool("offline", false, "build ungraded-assignments (ungraded bucket only), ungraded-submissions and email-assignments-status from the local mirror, the other commands need Canvas")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(exitUsage)
 	}
 
-	rl := rate.NewLimiter(rate.Every(10*time.Second), 1000) // 1000 requests every 10 seconds
-	client := canvas.NewAPIClient(baseURL, accessToken, pageSize, http.DefaultClient, rl)
-
-	accountID := 111
-
-	// courses, err := client.GetCoursesByAccountID(133, StudentCourseEnrollment)
-	// if err != nil {
-	// 	log.Fatal(err)
-	// }
-	// for i, course := range courses {
-	// 	fmt.Println(i+1, " ", course.Name, " ", course.Account.Name)
-	// }
-
-	// for _, qualification := range canvas.Qualifications {
-
-	// }
-
-	// submissions, err := client.GetUngradedSubmissionsByAccount(account)
-	// if err != nil {
-	// 	log.Fatal(err)
-	// }
+	var selected *command
+	for _, command := range commands {
+		if command.name == flag.Arg(0) {
+			selected = command
+		}
+	}
+	if selected == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(exitUsage)
+	}
 
-	account, err := client.GetAccountByID(accountID)
+	err := runCommand(selected, *profileName, *concurrency, *offline, flag.Args()[1:])
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		os.Exit(exitCode(err))
 	}
+}
 
-	assignments, err := client.GetAssignmentsByAccount(account, "ungraded")
-	if err != nil {
-		log.Fatal(err)
+func runCommand(command *command, profileName string, concurrency int, offline bool, args []string) error {
+	if offline && !command.mirrored && !command.offline {
+		return &usageErr{fmt.Sprintf("%s cannot run from the mirror", command.name)}
+	}
+	if offline && command.offlineArgs != nil {
+		if err := command.offlineArgs(args); err != nil {
+			return err
+		}
 	}
 
-	err = csv.ExportAssignmentsStatus(assignments, account)
+	profile, err := loadProfile(profileName)
 	if err != nil {
-		fmt.Println("Failed exporting assignments status")
-		return
+		return err
+	}
+
+	if offline && command.mirrored {
+		offlineMirror, err = mirror.Open(mirrorPath)
+		if err != nil {
+			return err
+		}
+		defer offlineMirror.Close()
 	}
 
-	// err = csv.ExportUngradedSubmissions(submissions, account)
-	// if err != nil {
-	// 	fmt.Println("Failed exporting ungraded submissions")
-	// }
+	client, err := newClient(profile, concurrency)
+	if err != nil && !command.offline && offlineMirror == nil {
+		return err
+	}
+	if client == nil {
+		client = canvas.NewAPIClient(profile.BaseURL, "", profile.PageSize, nil, nil)
+		client.Journal = canvas.NewJournal(profile.JournalPath)
+	}
 
-	fmt.Print("Successfully exported assignments status")
+	return command.run(client, args)
 }
 
 func getenv(key string, other string) string {
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/mirror"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// reportSource is what the mirrored reports read, Canvas or with -offline the mirror.
+type reportSource interface {
+	GetAccountByID(accountID int) (*canvas.Account, error)
+	GetAssignmentsByAccount(account *canvas.Account, bucket canvas.AssignmentBucket, opts *canvas.CourseQueryOptions) ([]*canvas.Assignment, error)
+	GetUngradedSubmissionsByAccount(account *canvas.Account, opts *canvas.CourseQueryOptions) ([]*canvas.Submission, error)
+}
+
+// offlineMirror is opened by runCommand when -offline is set.
+var offlineMirror *mirror.Mirror
+
+func source(client *canvas.APIClient) reportSource {
+	if offlineMirror != nil {
+		return offlineMirror
+	}
+
+	return client
+}
+
+func mirrorSync(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("mirror-sync", flag.ExitOnError)
+	accounts := accountFlags(flags)
+	full := flags.Bool("full", false, "fetch every course and submission again instead of the changes since the last sync")
+	flags.Parse(args)
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+
+	m, err := mirror.Open(mirrorPath)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tCOURSES\tSKIPPED\tREMOVED\tASSIGNMENTS\tSUBMISSIONS\tFINISHED")
+	defer w.Flush()
+	for _, accountID := range accountIDs {
+		result, err := m.Sync(client, accountID, *full)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\n", result.Account, result.Courses, result.Skipped, result.Removed, result.Assignments, result.Submissions, result.Finished)
+	}
+
+	return nil
+}
+
+func mirrorStatus(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("mirror-status", flag.ExitOnError)
+	flags.Parse(args)
+
+	m, err := mirror.Open(mirrorPath)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT ID\tACCOUNT\tCOURSES\tASSIGNMENTS\tSUBMISSIONS\tSYNCED AT")
+	for _, status := range statuses {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%s\n", status.AccountID, status.Account, status.Courses, status.Assignments, status.Submissions, status.SyncedAt)
+	}
+	w.Flush()
+
+	fmt.Printf("\nMirror: %s\n", m.Path)
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// nudgeTeachers messages every teacher of the accounts with the ungraded submissions in their sections.
+func nudgeTeachers(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("nudge-teachers", flag.ExitOnError)
+	accounts := accountFlags(flags)
+	courses := courseFlags(flags)
+	subject := flags.String("subject", canvas.DefaultNudgeSubject, "message subject")
+	templateFile := flags.String("template", "", "text/template file for the message body, executed with each teacher's nudge")
+	dryRun := flags.Bool("dry-run", false, "print and export the messages without sending them")
+	yes := flags.Bool("yes", false, "send without asking for confirmation")
+	flags.Parse(args)
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+
+	opts, err := courses()
+	if err != nil {
+		return err
+	}
+
+	bodyTemplate := ""
+	if *templateFile != "" {
+		content, err := os.ReadFile(*templateFile)
+		if err != nil {
+			return err
+		}
+		bodyTemplate = string(content)
+	}
+
+	controller := canvas.NewController(client)
+	for _, accountID := range accountIDs {
+		err := nudgeAccount(client, controller, accountID, opts, *subject, bodyTemplate, *dryRun, *yes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nudgeAccount previews, confirms and sends the nudges of one account.
+func nudgeAccount(client *canvas.APIClient, controller *canvas.Controller, accountID int, opts *canvas.CourseQueryOptions, subject, bodyTemplate string, dryRun, yes bool) error {
+	account, err := client.GetAccountByID(accountID)
+	if err != nil {
+		return err
+	}
+
+	assignments, err := client.GetAssignmentsByAccount(account, canvas.UngradedBucket, opts)
+	if err != nil {
+		return err
+	}
+
+	plan, err := controller.PreviewGradingNudges(assignments, subject, bodyTemplate)
+	if err != nil {
+		return err
+	}
+
+	for _, nudge := range plan.Nudges {
+		fmt.Printf("--- To: %s (%d sections, %d ungraded)\nSubject: %s\n\n%s\n\n", nudge.TeacherName, nudge.Sections, nudge.NeedsGrading, nudge.Subject, nudge.Body)
+	}
+	for _, assignment := range plan.Unassigned {
+		fmt.Printf("No teacher in %s / %s for %s (%d ungraded)\n", assignment.CourseName, assignment.Section, assignment.Name, assignment.NeedingGradingSection)
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run, %d messages not sent\n", len(plan.Nudges))
+		return csv.ExportGradingNudges(plan.Nudges, accountID)
+	}
+
+	if len(plan.Nudges) == 0 {
+		fmt.Printf("No teachers of %s have ungraded submissions\n", account.Name)
+		return nil
+	}
+
+	confirmed := yes
+	if !confirmed {
+		fmt.Printf("Send %d messages to teachers of %s in Canvas? [y/N] ", len(plan.Nudges), account.Name)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		confirmed = answer == "y" || answer == "yes"
+	}
+
+	if !confirmed {
+		fmt.Println("Cancelled, nothing was sent")
+		return nil
+	}
+
+	nudges, err := controller.SendGradingNudges(plan)
+	if err != nil {
+		return err
+	}
+
+	err = csv.ExportGradingNudges(nudges, accountID)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, nudge := range nudges {
+		if nudge.Sent {
+			sent++
+		} else {
+			fmt.Printf("Failed to message %s: %s\n", nudge.TeacherName, nudge.Error)
+		}
+		if nudge.JournalError != "" {
+			fmt.Printf("Messaged %s but not journaled as sent: %s\n", nudge.TeacherName, nudge.JournalError)
+		}
+	}
+	fmt.Printf("Sent %d of %d messages\n", sent, len(nudges))
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+)
+
+// masteryReport exports a student's mastery of every outcome in the qualifications.
+func masteryReport(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("mastery-report", flag.ExitOnError)
+	sisID := flags.String("student", "", "SIS ID of the student")
+	accounts := accountFlags(flags)
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	if *sisID == "" {
+		return usageError(flags, "-student is required")
+	}
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+	out.Accounts = accountIDs
+
+	user, err := client.GetUserBySisID(*sisID)
+	if err != nil {
+		return err
+	}
+
+	controller := canvas.NewController(client)
+	report := []*canvas.OutcomeMastery{}
+	for _, accountID := range accountIDs {
+		qualification, err := qualificationOf(client, accountID)
+		if err != nil {
+			return err
+		}
+
+		_report, err := controller.GetMasteryReportByStudent(qualification, user)
+		if err != nil {
+			return err
+		}
+		report = append(report, _report...)
+	}
+
+	err = out.write(flags, report, exports{
+		"csv": func() error {
+			return csv.ExportOutcomeMastery(report, user.SISUserID)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	summary := make(map[canvas.MasteryStatus]int)
+	for _, row := range report {
+		summary[row.Status]++
+	}
+	fmt.Fprintf(out.console(), "%s: %d mastered, %d not yet mastered, %d not assessed\n", user.Name,
+		summary[canvas.MasteredOutcome],
+		summary[canvas.NotMasteredOutcome],
+		summary[canvas.NotAssessedOutcome],
+	)
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Exit codes, anything not listed exits with 1
+const (
+	exitUsage = 2
+	exitAuth  = 3
+)
+
+var errMissingToken = fmt.Errorf("%w, set CANVAS_ACCESS_TOKEN or use a profile", canvas.ErrMissingToken)
+
+type usageErr struct {
+	message string
+}
+
+func (e *usageErr) Error() string {
+	return e.message
+}
+
+// usageError prints the flags of the command and returns an error that exits with exitUsage.
+func usageError(flags *flag.FlagSet, format string, args ...interface{}) error {
+	flags.Usage()
+	return &usageErr{fmt.Sprintf(format, args...)}
+}
+
+func exitCode(err error) int {
+	var usage *usageErr
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, canvas.ErrMissingToken), errors.Is(err, canvas.ErrUnauthorized):
+		return exitAuth
+	default:
+		return 1
+	}
+}
+
+type output struct {
+	Dir    string
+	Format string
+	// Written into the metadata of JSON exports
+	Accounts []int
+}
+
+func outputFlags(flags *flag.FlagSet) *output {
+	o := &output{}
+	flags.StringVar(&o.Dir, "out", "", "folder for exported files, the working directory by default")
+	flags.StringVar(&o.Format, "format", "csv", "csv writes files, json or ndjson print to stdout for other tools unless -out is set")
+	return o
+}
+
+// exports are the file writers of a report keyed by format.
+type exports map[string]func() error
+
+// write exports rows as JSON or NDJSON, or runs the export of the format with the output folder set.
+func (o *output) write(flags *flag.FlagSet, rows interface{}, exports exports) error {
+	if o.json() {
+		return o.writeJSON(flags, rows)
+	}
+
+	export := exports[o.Format]
+	if export == nil {
+		formats := []string{}
+		for format := range exports {
+			formats = append(formats, format)
+		}
+		sort.Strings(formats)
+		return usageError(flags, "unknown -format %q, use %s, json or ndjson", o.Format, strings.Join(formats, ", "))
+	}
+
+	csv.OutputDir = o.Dir
+	return export()
+}
+
+// writeJSON records the command and the flags it was run with in the metadata.
+func (o *output) writeJSON(flags *flag.FlagSet, rows interface{}) error {
+	metadata := csv.NewMetadata(flags.Name(), o.Accounts)
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name != "out" && f.Name != "format" {
+			metadata.Filters[f.Name] = f.Value.String()
+		}
+	})
+
+	if o.Dir == "" {
+		if o.Format == "ndjson" {
+			return csv.WriteNDJSON(os.Stdout, metadata, rows)
+		}
+		return csv.WriteJSON(os.Stdout, metadata, rows)
+	}
+
+	csv.OutputDir = o.Dir
+	export := csv.ExportJSON
+	if o.Format == "ndjson" {
+		export = csv.ExportNDJSON
+	}
+	path, err := export(metadata, rows)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.console(), "Exported %d rows to %s\n", metadata.Rows, path)
+	return nil
+}
+
+// json exports hold every account in one file, so commands gather the rows of all accounts first.
+func (o *output) json() bool {
+	return o.Format == "json" || o.Format == "ndjson"
+}
+
+// console is where commands print their summary, stderr when the export itself goes to stdout.
+func (o *output) console() io.Writer {
+	if o.Dir == "" && o.json() {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// printJSON prints v for commands that show a single value rather than report rows.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// accountFlags takes an account by ID or a qualification by name, "all" means every qualification.
+func accountFlags(flags *flag.FlagSet) func() ([]int, error) {
+	accountID := flags.Int("account", 0, "account ID, e.g. of a qualification")
+	qualification := flags.String("qualification", "", `qualification name or part of it, "all" for every qualification`)
+
+	return func() ([]int, error) {
+		if *accountID != 0 {
+			return []int{*accountID}, nil
+		}
+
+		if *qualification == "" {
+			return nil, usageError(flags, "-account or -qualification is required")
+		}
+
+		accountIDs := []int{}
+		for _, q := range canvas.Qualifications {
+			if *qualification == "all" || strings.Contains(strings.ToLower(q.Name), strings.ToLower(*qualification)) {
+				accountIDs = append(accountIDs, q.AccountID)
+			}
+		}
+		if len(accountIDs) == 0 {
+			return nil, usageError(flags, "no qualification matches %q, see the qualifications command", *qualification)
+		}
+
+		return accountIDs, nil
+	}
+}
+
+// courseFlags filters the courses of account-wide reports, only available courses unless -state says otherwise.
+func courseFlags(flags *flag.FlagSet) func() (*canvas.CourseQueryOptions, error) {
+	states := flags.String("state", string(canvas.AvailableCourse), "course states separated by commas: available, completed, unpublished, deleted or all")
+	termID := flags.Int("term", 0, "enrollment term ID, every term by default")
+	search := flags.String("search", "", "only courses whose name or code contains this")
+
+	return func() (*canvas.CourseQueryOptions, error) {
+		opts, err := courseQueryOptions(strings.Split(*states, ","), *termID, *search)
+		if err != nil {
+			return nil, usageError(flags, "%s", err)
+		}
+
+		return opts, nil
+	}
+}
+
+// courseQueryOptions builds the course filter of the -state, -term and -search flags and of schedule definitions.
+// No states means available courses.
+func courseQueryOptions(states []string, termID int, search string) (*canvas.CourseQueryOptions, error) {
+	opts := canvas.DefaultCourseQueryOptions()
+	opts.EnrollmentTermID = termID
+	opts.SearchTerm = strings.TrimSpace(search)
+
+	for _, state := range states {
+		state = strings.ToLower(strings.TrimSpace(state))
+		if state == "" {
+			continue
+		}
+
+		valid := false
+		for _, s := range canvas.AllCourseState {
+			valid = valid || string(s.Value) == state
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown course state %q", state)
+		}
+		opts.States = append(opts.States, canvas.CourseState(state))
+	}
+	if len(opts.States) == 0 {
+		opts.States = []canvas.CourseState{canvas.AvailableCourse}
+	}
+
+	return opts, nil
+}
+
+// qualificationOf names an account the way the qualification reports show it, with the Canvas account name
+// for accounts that aren't a known qualification.
+func qualificationOf(client *canvas.APIClient, accountID int) (canvas.Qualification, error) {
+	for _, qualification := range canvas.Qualifications {
+		if qualification.AccountID == accountID {
+			return qualification, nil
+		}
+	}
+
+	account, err := client.GetAccountByID(accountID)
+	if err != nil {
+		return canvas.Qualification{}, err
+	}
+
+	return canvas.Qualification{AccountID: account.ID, Name: account.Name}, nil
+}
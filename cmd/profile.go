@@ -0,0 +1,102 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Profile is one Canvas instance or token to run against, kept in profiles.json in the user config
+// folder (or CANVAS_PROFILES):
+//
+//	{"test": {"base_url": "https://example.test.instructure.com/api/v1", "access_token_env": "CANVAS_TEST_TOKEN"}}
+//
+// Empty fields fall back to the CANVAS_* environment variables.
+type Profile struct {
+	BaseURL        string `json:"base_url"`
+	AccessToken    string `json:"access_token"`
+	AccessTokenEnv string `json:"access_token_env"`
+	PageSize       int    `json:"page_size"`
+	UseGraphQL     *bool  `json:"use_graphql"`
+	JournalPath    string `json:"journal_path"`
+}
+
+func defaultProfilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "canvas-profiles.json"
+	}
+
+	return filepath.Join(dir, "canvas-desktop", "profiles.json")
+}
+
+func loadProfile(name string) (*Profile, error) {
+	profile := &Profile{}
+	if name != "" {
+		path := getenv("CANVAS_PROFILES", defaultProfilesPath())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read profiles %s: %w", path, err)
+		}
+
+		profiles := make(map[string]*Profile)
+		if err := json.Unmarshal(content, &profiles); err != nil {
+			return nil, fmt.Errorf("cannot read profiles %s: %w", path, err)
+		}
+
+		if profiles[name] == nil {
+			return nil, &usageErr{fmt.Sprintf("unknown profile %q in %s", name, path)}
+		}
+		profile = profiles[name]
+	}
+
+	if profile.BaseURL == "" {
+		profile.BaseURL = getenv("CANVAS_BASE_URL", "https://skillsaustralia.instructure.com/api/v1")
+	}
+	if profile.AccessTokenEnv != "" {
+		profile.AccessToken = os.Getenv(profile.AccessTokenEnv)
+	}
+	if profile.AccessToken == "" {
+		profile.AccessToken = getenv("CANVAS_ACCESS_TOKEN", "")
+	}
+	if profile.PageSize == 0 {
+		pageSize, err := strconv.Atoi(getenv("CANVAS_PAGE_SIZE", "100"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CANVAS_PAGE_SIZE: %w", err)
+		}
+		profile.PageSize = pageSize
+	}
+	if profile.UseGraphQL == nil {
+		useGraphQL, err := strconv.ParseBool(getenv("CANVAS_USE_GRAPHQL", "false"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CANVAS_USE_GRAPHQL: %w", err)
+		}
+		profile.UseGraphQL = &useGraphQL
+	}
+	if profile.JournalPath == "" {
+		profile.JournalPath = getenv("CANVAS_JOURNAL_PATH", canvas.DefaultJournalPath())
+	}
+
+	return profile, nil
+}
+
+func newClient(profile *Profile, concurrency int) (*canvas.APIClient, error) {
+	if profile.AccessToken == "" {
+		return nil, errMissingToken
+	}
+
+	rl := rate.NewLimiter(rate.Every(10*time.Second), 1000) // 1000 requests every 10 seconds
+	client := canvas.NewAPIClient(profile.BaseURL, profile.AccessToken, profile.PageSize, http.DefaultClient, rl)
+	client.UseGraphQL = *profile.UseGraphQL
+	client.Journal = canvas.NewJournal(profile.JournalPath)
+	client.Concurrency = concurrency
+
+	return client, nil
+}
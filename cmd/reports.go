@@ -0,0 +1,310 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type ungradedAssignmentsFlags struct {
+	flags    *flag.FlagSet
+	accounts func() ([]int, error)
+	courses  func() (*canvas.CourseQueryOptions, error)
+	bucket   *string
+	out      *output
+}
+
+func newUngradedAssignmentsFlags() *ungradedAssignmentsFlags {
+	flags := flag.NewFlagSet("ungraded-assignments", flag.ExitOnError)
+	return &ungradedAssignmentsFlags{
+		flags:    flags,
+		accounts: accountFlags(flags),
+		courses:  courseFlags(flags),
+		bucket:   flags.String("bucket", string(canvas.UngradedBucket), "past, overdue, undated, ungraded, unsubmitted, upcoming or future, only ungraded with -offline"),
+		out:      outputFlags(flags),
+	}
+}
+
+// ungradedAssignmentsOffline rejects the buckets the mirror can't answer, the others depend on the user asking.
+func ungradedAssignmentsOffline(args []string) error {
+	f := newUngradedAssignmentsFlags()
+	f.flags.Parse(args)
+	if *f.bucket != string(canvas.UngradedBucket) {
+		return usageError(f.flags, "-bucket %s cannot be read from the mirror, only %s", *f.bucket, canvas.UngradedBucket)
+	}
+
+	return nil
+}
+
+// ungradedAssignments exports the assignment status of every course in the accounts, split by campus.
+func ungradedAssignments(client *canvas.APIClient, args []string) error {
+	f := newUngradedAssignmentsFlags()
+	f.flags.Parse(args)
+
+	accountIDs, err := f.accounts()
+	if err != nil {
+		return err
+	}
+	f.out.Accounts = accountIDs
+
+	opts, err := f.courses()
+	if err != nil {
+		return err
+	}
+
+	valid := false
+	for _, b := range canvas.AllAssignmentBucket {
+		valid = valid || string(b.Value) == *f.bucket
+	}
+	if !valid {
+		return usageError(f.flags, "unknown -bucket %q", *f.bucket)
+	}
+
+	reports := source(client)
+	all := []*canvas.Assignment{}
+	for _, accountID := range accountIDs {
+		account, err := reports.GetAccountByID(accountID)
+		if err != nil {
+			return err
+		}
+
+		assignments, err := reports.GetAssignmentsByAccount(account, canvas.AssignmentBucket(*f.bucket), opts)
+		if err != nil {
+			return err
+		}
+
+		if f.out.json() {
+			all = append(all, assignments...)
+			continue
+		}
+
+		err = f.out.write(f.flags, assignments, exports{
+			"csv": func() error {
+				_, err := csv.ExportAssignmentsStatus(assignments, account)
+				return err
+			},
+			"xlsx": func() error {
+				_, err := csv.ExportAssignmentsStatusXLSX(assignments, account)
+				return err
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.out.json() {
+		return f.out.write(f.flags, all, nil)
+	}
+	return nil
+}
+
+// ungradedSubmissions exports every submission waiting to be graded in the accounts.
+func ungradedSubmissions(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("ungraded-submissions", flag.ExitOnError)
+	accounts := accountFlags(flags)
+	courses := courseFlags(flags)
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	accountIDs, err := accounts()
+	if err != nil {
+		return err
+	}
+	out.Accounts = accountIDs
+
+	opts, err := courses()
+	if err != nil {
+		return err
+	}
+
+	reports := source(client)
+	all := []*canvas.Submission{}
+	for _, accountID := range accountIDs {
+		account, err := reports.GetAccountByID(accountID)
+		if err != nil {
+			return err
+		}
+
+		submissions, err := reports.GetUngradedSubmissionsByAccount(account, opts)
+		if err != nil {
+			return err
+		}
+
+		if out.json() {
+			all = append(all, submissions...)
+			continue
+		}
+
+		err = out.write(flags, submissions, exports{
+			"csv": func() error {
+				return csv.ExportUngradedSubmissions(submissions, account)
+			},
+			"xlsx": func() error {
+				_, err := csv.ExportUngradedSubmissionsXLSX(submissions, account)
+				return err
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if out.json() {
+		return out.write(flags, all, nil)
+	}
+	return nil
+}
+
+// studentResults exports a student's result in every assignment of their courses.
+func studentResults(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("student-results", flag.ExitOnError)
+	studentID := flags.String("student", "", "student SIS ID")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	if *studentID == "" {
+		return usageError(flags, "-student is required")
+	}
+
+	user, err := client.GetUserBySisID(*studentID)
+	if err != nil {
+		return err
+	}
+
+	results, err := client.GetAssignmentsResultsByUser(user)
+	if err != nil {
+		return err
+	}
+
+	return out.write(flags, results, exports{
+		"csv": func() error {
+			return csv.ExportAssignmentsResults(results, user.SISUserID)
+		},
+		"xlsx": func() error {
+			_, err := csv.ExportAssignmentsResultsXLSX(results, user.SISUserID)
+			return err
+		},
+	})
+}
+
+// enrollmentResults exports a student's grades in every course they are enrolled in.
+func enrollmentResults(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("enrollment-results", flag.ExitOnError)
+	studentID := flags.String("student", "", "student SIS ID")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	if *studentID == "" {
+		return usageError(flags, "-student is required")
+	}
+
+	user, err := client.GetUserBySisID(*studentID)
+	if err != nil {
+		return err
+	}
+
+	results, err := client.GetAllEnrollmentsResultsByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	return out.write(flags, results, exports{
+		"csv": func() error {
+			return csv.ExportEnrollmentsResults(results, user.SISUserID)
+		},
+		"xlsx": func() error {
+			_, err := csv.ExportEnrollmentsResultsXLSX(results, user.SISUserID)
+			return err
+		},
+	})
+}
+
+// listQualifications prints the qualifications and their account IDs.
+func listQualifications(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("qualifications", flag.ExitOnError)
+	format := flags.String("format", "table", "table or json")
+	flags.Parse(args)
+
+	if *format == "json" {
+		return printJSON(canvas.Qualifications)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tQUALIFICATION")
+	for _, qualification := range canvas.Qualifications {
+		fmt.Fprintf(w, "%d\t%s\n", qualification.AccountID, qualification.Name)
+	}
+	return w.Flush()
+}
+
+// whoami prints the user of the access token, a quick check that the token and base URL work.
+func whoami(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("whoami", flag.ExitOnError)
+	format := flags.String("format", "table", "table or json")
+	flags.Parse(args)
+
+	user, err := client.GetSelf()
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		return printJSON(user)
+	}
+
+	fmt.Printf("%s (ID %d, login %s) on %s\n", user.Name, user.ID, user.LoginID, client.BaseURL)
+	return nil
+}
+
+// rosterResults exports the results of every student in a class list CSV.
+func rosterResults(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("roster-results", flag.ExitOnError)
+	filename := flags.String("file", "", "class list CSV with a Student ID, Login or Email column")
+	identifierType := flags.String("type", string(canvas.SISUserIdentifier), "sis_user_id, login_id, email or auto")
+	accountID := flags.Int("account", 0, "account to search for email identifiers, required for -type email, auto only searches emails with it")
+	perStudent := flags.Bool("per-student", false, "also write each student's enrollment and assignment files")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	if *filename == "" {
+		return usageError(flags, "-file is required")
+	}
+	if canvas.UserIdentifierType(*identifierType) == canvas.EmailIdentifier && *accountID == 0 {
+		return usageError(flags, "-account is required with -type email")
+	}
+
+	identifiers, err := csv.ReadRoster(*filename)
+	if err != nil {
+		return err
+	}
+
+	controller := canvas.NewController(client)
+	report, err := controller.GetRosterResults(*accountID, canvas.UserIdentifierType(*identifierType), identifiers)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range report.NotFound {
+		fmt.Fprintf(os.Stderr, "Not found: %s (%s)\n", user.Identifier, user.Reason)
+	}
+
+	return out.write(flags, report.Rows(), exports{
+		"csv": func() error {
+			path, err := csv.ExportRosterResults(report, *perStudent)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Exported %d students to %s, %d not found\n", len(report.Students), path, len(report.NotFound))
+			if *perStudent {
+				fmt.Printf("Per student files in %s\n", strings.TrimSuffix(path, ".csv"))
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"flag"
+	"fmt"
+)
+
+// rubricReport exports every student's rating per rubric criterion and lists the criteria nobody was rated on.
+func rubricReport(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("rubric-report", flag.ExitOnError)
+	courseID := flags.Int("course", 0, "Canvas course ID")
+	out := outputFlags(flags)
+	flags.Parse(args)
+
+	if *courseID == 0 {
+		return usageError(flags, "-course is required")
+	}
+
+	controller := canvas.NewController(client)
+	results, err := controller.GetRubricReportByCourse(*courseID)
+	if err != nil {
+		return err
+	}
+
+	err = out.write(flags, results, exports{
+		"csv": func() error {
+			return csv.ExportRubricReport(results, *courseID)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range results {
+		key := result.Assignment + "\x00" + result.CriterionID
+		if !result.NeverAssessed || seen[key] {
+			continue
+		}
+		seen[key] = true
+		fmt.Fprintf(out.console(), "Never assessed: %s - %s\n", result.Assignment, result.Criterion)
+	}
+	fmt.Fprintf(out.console(), "Exported %d criterion ratings, %d criteria never assessed\n", len(results), len(seen))
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
+	"canvas-desktop/email"
+	"canvas-desktop/schedule"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+)
+
+// scheduledReports are the report types a schedule definition can name.
+func scheduledReports(client *canvas.APIClient) map[string]schedule.Report {
+	return map[string]schedule.Report{
+		"ungraded-assignments": func(definition *schedule.Definition, dir string) error {
+			attachments := []*email.Attachment{}
+			err := forEachAccount(client, definition, dir, func(account *canvas.Account, opts *canvas.CourseQueryOptions) error {
+				assignments, err := client.GetAssignmentsByAccount(account, canvas.UngradedBucket, opts)
+				if err != nil {
+					return err
+				}
+
+				paths, err := csv.ExportAssignmentsStatus(assignments, account)
+				if err != nil {
+					return err
+				}
+				for _, campus := range []string{canvas.PerthCampus, canvas.AdelaideCampus} {
+					attachments = append(attachments, &email.Attachment{Path: paths[campus], Segment: campus})
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			return emailScheduledReport(definition, attachments)
+		},
+		"ungraded-submissions": func(definition *schedule.Definition, dir string) error {
+			err := forEachAccount(client, definition, dir, func(account *canvas.Account, opts *canvas.CourseQueryOptions) error {
+				submissions, err := client.GetUngradedSubmissionsByAccount(account, opts)
+				if err != nil {
+					return err
+				}
+
+				return csv.ExportUngradedSubmissions(submissions, account)
+			})
+			if err != nil {
+				return err
+			}
+
+			return emailScheduledReport(definition, dirAttachments(dir))
+		},
+		"inactive-students": func(definition *schedule.Definition, dir string) error {
+			controller := canvas.NewController(client)
+			err := forEachAccount(client, definition, dir, func(account *canvas.Account, opts *canvas.CourseQueryOptions) error {
+				students, err := controller.GetInactiveStudentsByAccountID(account.ID, opts, canvas.InactivityCriteria{InactiveDays: 14})
+				if err != nil {
+					return err
+				}
+
+				return csv.ExportInactiveStudents(students, account.ID)
+			})
+			if err != nil {
+				return err
+			}
+
+			return emailScheduledReport(definition, dirAttachments(dir))
+		},
+	}
+}
+
+// forEachAccount runs report with the exports going to dir, for the definition's accounts or every qualification
+// and the courses of the definition's filter.
+func forEachAccount(client *canvas.APIClient, definition *schedule.Definition, dir string, report func(*canvas.Account, *canvas.CourseQueryOptions) error) error {
+	opts, err := courseQueryOptions(definition.States, definition.Term, definition.Search)
+	if err != nil {
+		return err
+	}
+
+	csv.OutputDir = dir
+	defer func() {
+		csv.OutputDir = ""
+	}()
+
+	accountIDs := definition.Accounts
+	if len(accountIDs) == 0 {
+		for _, qualification := range canvas.Qualifications {
+			accountIDs = append(accountIDs, qualification.AccountID)
+		}
+	}
+
+	for _, accountID := range accountIDs {
+		account, err := client.GetAccountByID(accountID)
+		if err != nil {
+			return err
+		}
+
+		if err := report(account, opts); err != nil {
+			return fmt.Errorf("account %d: %w", accountID, err)
+		}
+	}
+
+	return nil
+}
+
+func dirAttachments(dir string) []*email.Attachment {
+	attachments := []*email.Attachment{}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		attachments = append(attachments, &email.Attachment{Path: filepath.Join(dir, entry.Name())})
+	}
+
+	return attachments
+}
+
+func emailScheduledReport(definition *schedule.Definition, attachments []*email.Attachment) error {
+	if definition.EmailProfile == "" {
+		return nil
+	}
+
+	profile, err := loadEmailProfile(definition.EmailProfile)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := email.SendReports(profile, attachments, definition)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if !delivery.Sent && delivery.Error != "" {
+			return fmt.Errorf("emailing %s: %s", delivery.Email, delivery.Error)
+		}
+	}
+	return nil
+}
+
+// runSchedule runs the reports of the schedule config on their cron expressions until interrupted,
+// or one report straight away with -run.
+func runSchedule(client *canvas.APIClient, args []string) error {
+	flags := flag.NewFlagSet("schedule", flag.ExitOnError)
+	configPath := flags.String("config", "schedule.json", "schedule config file")
+	runName := flags.String("run", "", "run this report now and exit")
+	status := flags.Bool("status", false, "list the recorded runs and exit")
+	flags.Parse(args)
+
+	config, err := schedule.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	scheduler := schedule.NewScheduler(config, scheduledReports(client))
+
+	if *status {
+		runs, err := scheduler.Runs()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "STARTED\tFINISHED\tNAME\tSTATUS\tFILES\tFOLDER\tERROR")
+		for _, run := range runs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", run.Started, run.Finished, run.Name, run.Status, len(run.Files), run.Dir, run.Error)
+		}
+		w.Flush()
+		return nil
+	}
+
+	if *runName != "" {
+		definition, err := scheduler.Definition(*runName)
+		if err != nil {
+			return err
+		}
+
+		run := scheduler.Run(definition)
+		fmt.Printf("%s %s, %d files in %s\n", run.Name, run.Status, len(run.Files), run.Dir)
+		if run.Status != schedule.SucceededRun {
+			return fmt.Errorf("%s", run.Error)
+		}
+		return nil
+	}
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	return scheduler.Start(stop)
+}
@@ -3,10 +3,10 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"canvas-desktop/canvas"
+	"canvas-desktop/csv"
 
 	"github.com/gocarina/gocsv"
 	"github.com/ninja-software/terror/v2"
@@ -29,43 +29,60 @@ func (a *App) ExportAssignmentsResults(results []*canvas.AssignmentResult, userS
 }
 
 func (a *App) ExportAssignmentsStatus(assignments []*canvas.Assignment, account *canvas.Account) error {
-	time := time.Now().Format("2006-01-02-15-04-05")
-	name := canvas.ReplaceSpaceInStr(account.Name, "_")
+	_, err := csv.ExportAssignmentsStatus(assignments, account)
+	return err
+}
 
-	filePerth, err := os.Create(fmt.Sprintf("PERTH-%s-%s-assignments_status.csv", name, time))
-	if err != nil {
-		return err
-	}
-	defer filePerth.Close()
+// ExportAssignmentsStatusXLSX returns the path of the workbook.
+func (a *App) ExportAssignmentsStatusXLSX(assignments []*canvas.Assignment, account *canvas.Account) (string, error) {
+	return csv.ExportAssignmentsStatusXLSX(assignments, account)
+}
 
-	fileAdelaide, err := os.Create(fmt.Sprintf("ADL-%s-%s-assignments_status.csv", name, time))
-	if err != nil {
-		return err
-	}
-	defer fileAdelaide.Close()
-
-	assignmentsPerth := []*canvas.Assignment{}
-	assignmentsAdelaide := []*canvas.Assignment{}
-
-	for _, assignment := range assignments {
-		if strings.Contains(assignment.Section, "ADL") ||
-			strings.Contains(assignment.Section, "ADELAIDE") ||
-			strings.Contains(assignment.Section, "Adelaide") {
-			assignmentsAdelaide = append(assignmentsAdelaide, assignment)
-		} else {
-			assignmentsPerth = append(assignmentsPerth, assignment)
-		}
-	}
+func (a *App) ReadGradeImport(filename string) ([]*canvas.GradeImportRow, error) {
+	return csv.ReadGradeImport(filename)
+}
 
-	err = gocsv.MarshalFile(&assignmentsAdelaide, fileAdelaide)
-	if err != nil {
-		return terror.Error(err, "cannot write rows to csv file")
-	}
+func (a *App) ExportGradeImportPlan(plan *canvas.GradeImportPlan) error {
+	return csv.ExportGradeImportPlan(plan)
+}
 
-	err = gocsv.MarshalFile(&assignmentsPerth, filePerth)
-	if err != nil {
-		return terror.Error(err, "cannot write rows to csv file")
-	}
+func (a *App) ExportFeedback(feedback []*canvas.SubmissionFeedback, name string) error {
+	return csv.ExportFeedback(feedback, name)
+}
 
-	return nil
+func (a *App) ExportRubricReport(results []*canvas.RubricCriterionResult, courseID int) error {
+	return csv.ExportRubricReport(results, courseID)
+}
+
+func (a *App) ExportGradeBreakdowns(breakdowns []*canvas.GradeBreakdown, courseID int) error {
+	return csv.ExportGradeBreakdowns(breakdowns, courseID)
+}
+
+func (a *App) ExportGradeChangeAudit(records []*canvas.GradeChangeRecord, name string) error {
+	return csv.ExportGradeChangeAudit(records, name)
+}
+
+func (a *App) ExportOutcomeMastery(report []*canvas.OutcomeMastery, userSisID string) error {
+	return csv.ExportOutcomeMastery(report, userSisID)
+}
+
+func (a *App) ExportInactiveStudents(students []*canvas.InactiveStudent, accountID int) error {
+	return csv.ExportInactiveStudents(students, accountID)
+}
+
+func (a *App) ExportStudentEngagement(engagement []*canvas.StudentEngagement, accountID int) error {
+	return csv.ExportStudentEngagement(engagement, accountID)
+}
+
+func (a *App) ExportGradingNudges(nudges []*canvas.GradingNudge, accountID int) error {
+	return csv.ExportGradingNudges(nudges, accountID)
+}
+
+func (a *App) ReadRoster(filename string) ([]string, error) {
+	return csv.ReadRoster(filename)
+}
+
+// ExportRosterResults returns the path of the combined CSV.
+func (a *App) ExportRosterResults(report *canvas.StudentAssessmentsReport, perStudent bool) (string, error) {
+	return csv.ExportRosterResults(report, perStudent)
 }
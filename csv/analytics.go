@@ -0,0 +1,26 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	"fmt"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/ninja-software/terror/v2"
+)
+
+func ExportStudentEngagement(engagement []*canvas.StudentEngagement, accountID int) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-student_engagement.csv", accountID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&engagement, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	encodingcsv "encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/ninja-software/terror/v2"
+)
+
+// writeStudentAssessments writes the enrollment and assignment results of each student, and the IDs
+// that weren't found, into dir.
+func writeStudentAssessments(dir string, report *canvas.StudentAssessmentsReport) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return terror.Error(err, "cannot create student assessments folder")
+	}
+
+	for _, student := range report.Students {
+		name := canvas.ReplaceSpaceInStr(student.Identifier, "_")
+
+		err := writeRows(filepath.Join(dir, fmt.Sprintf("%s-enrollments_results.csv", name)), &student.Enrollments)
+		if err != nil {
+			return err
+		}
+
+		err = writeRows(filepath.Join(dir, fmt.Sprintf("%s-assignment_results.csv", name)), &student.Assignments)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(report.NotFound) > 0 {
+		err := writeRows(filepath.Join(dir, "not_found.csv"), &report.NotFound)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeRows(path string, rows interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(rows, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
+
+// rosterColumns are the headers ReadRoster looks for, in order, before falling back to the first column.
+var rosterColumns = []string{"student id", "sis id", "sis_user_id", "login", "login_id", "email", "id"}
+
+// ReadRoster reads the student identifiers of a class list. The CSV needs a header row, the column is
+// picked by name (Student ID, SIS ID, Login, Email, ...) or is the first one.
+func ReadRoster(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := encodingcsv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, terror.Error(err, "cannot read rows from csv file")
+	}
+	if len(records) == 0 {
+		return []string{}, nil
+	}
+
+	column := rosterColumn(records[0])
+	identifiers := []string{}
+	for _, record := range records[1:] {
+		if column < len(record) && strings.TrimSpace(record[column]) != "" {
+			identifiers = append(identifiers, strings.TrimSpace(record[column]))
+		}
+	}
+
+	return identifiers, nil
+}
+
+func rosterColumn(header []string) int {
+	for _, name := range rosterColumns {
+		for i, title := range header {
+			// Excel saves UTF-8 CSVs with a byte order mark
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(title, "\ufeff")), name) {
+				return i
+			}
+		}
+	}
+
+	return 0
+}
+
+// ExportRosterResults writes every student's results in one CSV and returns its path.
+// perStudent also writes the files of each student into a folder next to it, named like the CSV without .csv.
+func ExportRosterResults(report *canvas.StudentAssessmentsReport, perStudent bool) (string, error) {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	path, err := outputPath(fmt.Sprintf("%s-roster_results.csv", time))
+	if err != nil {
+		return "", err
+	}
+
+	rows := report.Rows()
+	if err := writeRows(path, &rows); err != nil {
+		return "", err
+	}
+
+	if perStudent {
+		if err := writeStudentAssessments(strings.TrimSuffix(path, ".csv"), report); err != nil {
+			return "", err
+		}
+	}
+
+	return path, nil
+}
@@ -3,7 +3,6 @@ package csv
 import (
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"canvas-desktop/canvas"
@@ -14,7 +13,7 @@ import (
 
 func ExportAssignmentsResults(results []*canvas.AssignmentResult, userSisID string) error {
 	time := time.Now().Format("2006-01-02-15-04-05")
-	file, err := os.Create(fmt.Sprintf("%s-%s-assignment_results.csv", userSisID, time))
+	file, err := createFile(fmt.Sprintf("%s-%s-assignment_results.csv", userSisID, time))
 	if err != nil {
 		return err
 	}
@@ -28,47 +27,39 @@ func ExportAssignmentsResults(results []*canvas.AssignmentResult, userSisID stri
 	return nil
 }
 
-func ExportAssignmentsStatus(assignments []*canvas.Assignment, account *canvas.Account) error {
-	fmt.Println(len(assignments))
+// ExportAssignmentsStatus writes one file per campus and returns the file paths keyed by campus.
+func ExportAssignmentsStatus(assignments []*canvas.Assignment, account *canvas.Account) (map[string]string, error) {
 	time := time.Now().Format("2006-01-02-15-04-05")
 	name := canvas.ReplaceSpaceInStr(account.Name, "_")
 
-	filePerth, err := os.Create(fmt.Sprintf("PERTH-%s-%s-assignments_status.csv", name, time))
-	if err != nil {
-		return err
+	assignmentsByCampus := map[string][]*canvas.Assignment{
+		canvas.PerthCampus:    {},
+		canvas.AdelaideCampus: {},
 	}
-	defer filePerth.Close()
-
-	fileAdelaide, err := os.Create(fmt.Sprintf("ADL-%s-%s-assignments_status.csv", name, time))
-	if err != nil {
-		return err
+	for _, assignment := range assignments {
+		campus := canvas.CampusOf(assignment.Section)
+		assignmentsByCampus[campus] = append(assignmentsByCampus[campus], assignment)
 	}
-	defer fileAdelaide.Close()
-
-	assignmentsPerth := []*canvas.Assignment{}
-	assignmentsAdelaide := []*canvas.Assignment{}
 
-	for _, assignment := range assignments {
-		if strings.Contains(assignment.Section, "ADL") ||
-			strings.Contains(assignment.Section, "ADELAIDE") ||
-			strings.Contains(assignment.Section, "Adelaide") {
-			assignmentsAdelaide = append(assignmentsAdelaide, assignment)
-		} else {
-			assignmentsPerth = append(assignmentsPerth, assignment)
+	paths := make(map[string]string)
+	for campus, _assignments := range assignmentsByCampus {
+		path, err := outputPath(fmt.Sprintf("%s-%s-%s-assignments_status.csv", campus, name, time))
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	err = gocsv.MarshalFile(&assignmentsAdelaide, fileAdelaide)
-	if err != nil {
-		return terror.Error(err, "cannot write rows to csv file")
-	}
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
 
-	err = gocsv.MarshalFile(&assignmentsPerth, filePerth)
-	if err != nil {
-		return terror.Error(err, "cannot write rows to csv file")
+		err = gocsv.MarshalFile(&_assignments, file)
+		file.Close()
+		if err != nil {
+			return nil, terror.Error(err, "cannot write rows to csv file")
+		}
+		paths[campus] = path
 	}
 
-	fmt.Println("Done exporting")
-
-	return nil
+	return paths, nil
 }
@@ -0,0 +1,27 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	"fmt"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/ninja-software/terror/v2"
+)
+
+// ExportGradingNudges writes one row per teacher with the message they get, or got once sent.
+func ExportGradingNudges(nudges []*canvas.GradingNudge, accountID int) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-grading_nudges.csv", accountID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&nudges, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
@@ -3,7 +3,6 @@ package csv
 import (
 	"canvas-desktop/canvas"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gocarina/gocsv"
@@ -12,7 +11,7 @@ import (
 
 func ExportEnrollmentsResults(results []*canvas.EnrollmentResult, userSisID string) error {
 	time := time.Now().Format("2006-01-02-15-04-05")
-	file, err := os.Create(fmt.Sprintf("%s-%s-enrollments_results.csv", userSisID, time))
+	file, err := createFile(fmt.Sprintf("%s-%s-enrollments_results.csv", userSisID, time))
 	if err != nil {
 		return err
 	}
@@ -25,3 +24,19 @@ func ExportEnrollmentsResults(results []*canvas.EnrollmentResult, userSisID stri
 
 	return nil
 }
+
+func ExportInactiveStudents(students []*canvas.InactiveStudent, accountID int) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-inactive_students.csv", accountID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&students, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package csv
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// OutputDir is the folder exports are written to, the working directory when empty.
+var OutputDir = ""
+
+func outputPath(name string) (string, error) {
+	if OutputDir == "" {
+		return name, nil
+	}
+
+	if err := os.MkdirAll(OutputDir, 0o755); err != nil {
+		return "", terror.Error(err, "cannot create output folder")
+	}
+
+	return filepath.Join(OutputDir, name), nil
+}
+
+func createFile(name string) (*os.File, error) {
+	path, err := outputPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Create(path)
+}
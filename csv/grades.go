@@ -0,0 +1,169 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/ninja-software/terror/v2"
+)
+
+// ReadGradeImport reads a CSV with Student ID, Assignment and Grade columns.
+func ReadGradeImport(filename string) ([]*canvas.GradeImportRow, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rows := []*canvas.GradeImportRow{}
+	err = gocsv.UnmarshalFile(file, &rows)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read rows from csv file")
+	}
+
+	return rows, nil
+}
+
+func ExportGradeImportPlan(plan *canvas.GradeImportPlan) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-grade_import_diff.csv", plan.CourseID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&plan.Changes, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
+
+func ExportGradeUpdateResults(results []*canvas.GradeUpdateResult, courseID int) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-grade_import_results.csv", courseID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&results, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
+
+func ExportRollbackReport(report *canvas.RollbackReport) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%s-%s-rollback.csv", report.OperationID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&report.Results, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
+
+type gradeBreakdownRow struct {
+	StudentID      string `csv:"Student ID"`
+	StudentName    string `csv:"Student Name"`
+	CourseName     string `csv:"Course"`
+	Group          string `csv:"Assignment Group"`
+	Weight         string `csv:"Weight"`
+	Points         string `csv:"Points"`
+	PointsPossible string `csv:"Points Possible"`
+	Dropped        int    `csv:"Dropped"`
+	CurrentScore   string `csv:"Current Score"`
+	FinalScore     string `csv:"Final Score"`
+	CanvasCurrent  string `csv:"Canvas Current Score"`
+	CanvasFinal    string `csv:"Canvas Final Score"`
+	Mismatch       bool   `csv:"Mismatch"`
+	Details        string `csv:"Details"`
+}
+
+// ExportGradeBreakdowns writes one row per assignment group and a Total row per student.
+func ExportGradeBreakdowns(breakdowns []*canvas.GradeBreakdown, courseID int) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-grade_breakdown.csv", courseID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows := []*gradeBreakdownRow{}
+	for _, breakdown := range breakdowns {
+		for _, group := range breakdown.Groups {
+			row := &gradeBreakdownRow{
+				StudentID:      breakdown.StudentID,
+				StudentName:    breakdown.StudentName,
+				CourseName:     breakdown.CourseName,
+				Group:          group.Group,
+				Points:         fmt.Sprintf("%g", group.Points),
+				PointsPossible: fmt.Sprintf("%g", group.PointsPossible),
+				Dropped:        group.Dropped,
+				CurrentScore:   formatPercent(group.CurrentScore),
+				FinalScore:     formatPercent(group.FinalScore),
+			}
+			if breakdown.Weighted {
+				row.Weight = fmt.Sprintf("%g%%", group.Weight)
+			}
+			rows = append(rows, row)
+		}
+
+		rows = append(rows, &gradeBreakdownRow{
+			StudentID:     breakdown.StudentID,
+			StudentName:   breakdown.StudentName,
+			CourseName:    breakdown.CourseName,
+			Group:         "Total",
+			CurrentScore:  formatPercent(breakdown.CurrentScore),
+			FinalScore:    formatPercent(breakdown.FinalScore),
+			CanvasCurrent: fmt.Sprintf("%.2f", breakdown.CanvasCurrentScore),
+			CanvasFinal:   fmt.Sprintf("%.2f", breakdown.CanvasFinalScore),
+			Mismatch:      breakdown.Mismatch,
+			Details:       breakdown.MismatchDetails,
+		})
+	}
+
+	err = gocsv.MarshalFile(&rows, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
+
+func formatPercent(score *float64) string {
+	if score == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%.2f", *score)
+}
+
+// name is the assignment, course, student or grader the log was read for
+func ExportGradeChangeAudit(records []*canvas.GradeChangeRecord, name string) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%s-%s-grade_changes.csv", canvas.ReplaceSpaceInStr(name, "_"), time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&records, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
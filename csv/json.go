@@ -0,0 +1,221 @@
+package csv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// Version is written into the metadata of JSON exports, set it when building a release with
+// -ldflags "-X canvas-desktop/csv.Version=1.2.0".
+var Version = "dev"
+
+// Metadata is the first record of JSON and NDJSON exports.
+type Metadata struct {
+	Report     string            `json:"report"`
+	Accounts   []int             `json:"accounts"`
+	RunAt      string            `json:"run_at"`
+	Filters    map[string]string `json:"filters"`
+	AppVersion string            `json:"app_version"`
+	Rows       int               `json:"rows"`
+}
+
+func NewMetadata(report string, accounts []int) *Metadata {
+	if accounts == nil {
+		accounts = []int{}
+	}
+
+	return &Metadata{
+		Report:     report,
+		Accounts:   accounts,
+		RunAt:      time.Now().UTC().Format(time.RFC3339),
+		Filters:    make(map[string]string),
+		AppVersion: Version,
+	}
+}
+
+// ExportJSON writes rows, a slice of report rows, as {"metadata": ..., "rows": [...]} and returns the file path.
+func ExportJSON(metadata *Metadata, rows interface{}) (string, error) {
+	return exportJSONFile(metadata, rows, "json", WriteJSON)
+}
+
+// ExportNDJSON writes the metadata on the first line and one row per line after it, and returns the file path.
+func ExportNDJSON(metadata *Metadata, rows interface{}) (string, error) {
+	return exportJSONFile(metadata, rows, "ndjson", WriteNDJSON)
+}
+
+func exportJSONFile(metadata *Metadata, rows interface{}, extension string, write func(io.Writer, *Metadata, interface{}) error) (string, error) {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	name := fmt.Sprintf("%s-%s.%s", time, metadata.Report, extension)
+	if len(metadata.Accounts) == 1 {
+		name = fmt.Sprintf("%d-%s", metadata.Accounts[0], name)
+	}
+
+	file, err := createFile(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	err = write(file, metadata, rows)
+	if err != nil {
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+func WriteJSON(w io.Writer, metadata *Metadata, rows interface{}) error {
+	records, err := jsonRecords(rows)
+	if err != nil {
+		return err
+	}
+	metadata.Rows = len(records)
+
+	content, err := json.Marshal(struct {
+		Metadata *Metadata          `json:"metadata"`
+		Rows     []*json.RawMessage `json:"rows"`
+	}{metadata, records})
+	if err != nil {
+		return terror.Error(err, "cannot marshal rows to json")
+	}
+
+	var indented bytes.Buffer
+	err = json.Indent(&indented, content, "", "  ")
+	if err != nil {
+		return terror.Error(err, "cannot indent json")
+	}
+	indented.WriteByte('\n')
+
+	_, err = indented.WriteTo(w)
+	if err != nil {
+		return terror.Error(err, "cannot write json")
+	}
+
+	return nil
+}
+
+func WriteNDJSON(w io.Writer, metadata *Metadata, rows interface{}) error {
+	records, err := jsonRecords(rows)
+	if err != nil {
+		return err
+	}
+	metadata.Rows = len(records)
+
+	header, err := json.Marshal(map[string]*Metadata{"metadata": metadata})
+	if err != nil {
+		return terror.Error(err, "cannot marshal metadata to json")
+	}
+
+	buffered := bufio.NewWriter(w)
+	buffered.Write(header)
+	buffered.WriteByte('\n')
+	for _, record := range records {
+		buffered.Write(*record)
+		buffered.WriteByte('\n')
+	}
+
+	err = buffered.Flush()
+	if err != nil {
+		return terror.Error(err, "cannot write ndjson")
+	}
+
+	return nil
+}
+
+func jsonRecords(rows interface{}) ([]*json.RawMessage, error) {
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice {
+		return nil, terror.Error(fmt.Errorf("rows are a %s", value.Kind()), "cannot export rows other than a slice")
+	}
+
+	records := make([]*json.RawMessage, value.Len())
+	for i := range records {
+		record, err := jsonRecord(value.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		records[i] = &record
+	}
+
+	return records, nil
+}
+
+// jsonRecord encodes the columns of the CSV export in field order. Keys are the json tag, or the
+// CSV header for fields only filled for the export, in snake case so every report names fields the same
+// way. Nested structs stay nested and Canvas timestamps are written as UTC ISO 8601.
+func jsonRecord(value reflect.Value) (json.RawMessage, error) {
+	if value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+	case reflect.String:
+		text := value.String()
+		if date, err := time.Parse(time.RFC3339, text); err == nil {
+			text = date.UTC().Format(time.RFC3339)
+		}
+		return json.Marshal(text)
+	default:
+		content, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, terror.Error(err, "cannot marshal field to json")
+		}
+		return content, nil
+	}
+
+	var record bytes.Buffer
+	record.WriteByte('{')
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		header := strings.Split(field.Tag.Get("csv"), ",")[0]
+		if header == "-" || !field.IsExported() {
+			continue
+		}
+
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" {
+			key = header
+		}
+		if key == "" {
+			key = field.Name
+		}
+
+		content, err := jsonRecord(value.Field(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if record.Len() > 1 {
+			record.WriteByte(',')
+		}
+		name, _ := json.Marshal(snakeCase(key))
+		record.Write(name)
+		record.WriteByte(':')
+		record.Write(content)
+	}
+	record.WriteByte('}')
+
+	return record.Bytes(), nil
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+var lowerUpper = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// snakeCase turns "Course Name", "due-at" and "CourseName" into course_name, due_at and course_name.
+func snakeCase(key string) string {
+	key = lowerUpper.ReplaceAllString(key, "${1}_${2}")
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(key), "_"), "_")
+}
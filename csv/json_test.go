@@ -0,0 +1,115 @@
+package csv
+
+import (
+	"bytes"
+	"canvas-desktop/canvas"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteNDJSON(t *testing.T) {
+	submission := &canvas.Submission{ID: 1, Account: "Automotive", CourseName: "Brakes", Grade: "A", SubmittedAt: "2024-03-01T10:00:00+10:30", Attempt: 2}
+	submission.User.SISUserID = "S1"
+	submission.User.Name = "Ann"
+
+	tests := []struct {
+		name string
+		rows interface{}
+		want string
+	}{
+		{
+			name: "submission",
+			rows: []*canvas.Submission{submission},
+			want: `{"qualification":"Automotive","course":"Brakes","term":"","user":{"sis_user_id":"S1","name":"Ann"},` +
+				`"assignment_name":"","due_at":"","grade":"A","submitted_at":"2024-02-29T23:30:00Z","graded_at":"","attempt":2,` +
+				`"late":false,"excused":false,"preview_url":"","resubmitted":false}`,
+		},
+		{
+			name: "assignment",
+			rows: []*canvas.Assignment{{ID: 1, Account: "Automotive", Name: "Essay", DueAt: "2024-03-01T10:00:00Z", NeedingGradingSection: 3}},
+			want: `{"qualification":"Automotive","course_name":"","term":"","name":"Essay","due_at":"2024-03-01T10:00:00Z",` +
+				`"unlock_at":"","lock_at":"","section":"","needs_grading_section":3,"teachers":"","status":"","published":false,"gradebook_url":""}`,
+		},
+		{
+			name: "no rows",
+			rows: []*canvas.Assignment{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			metadata := &Metadata{Report: "test", Accounts: []int{1}, RunAt: "2024-03-01T00:00:00Z", Filters: map[string]string{}, AppVersion: "dev"}
+			if err := WriteNDJSON(&out, metadata, test.rows); err != nil {
+				t.Fatalf("WriteNDJSON() error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			rows := 0
+			if test.want != "" {
+				rows = 1
+			}
+			header := fmt.Sprintf(`{"metadata":{"report":"test","accounts":[1],"run_at":"2024-03-01T00:00:00Z","filters":{},"app_version":"dev","rows":%d}}`, rows)
+			if lines[0] != header {
+				t.Errorf("WriteNDJSON() header\n%s\nwant\n%s", lines[0], header)
+			}
+			if len(lines) != rows+1 {
+				t.Fatalf("WriteNDJSON() wrote %d lines, want %d", len(lines), rows+1)
+			}
+			if rows > 0 && lines[1] != test.want {
+				t.Errorf("WriteNDJSON() row\n%s\nwant\n%s", lines[1], test.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	metadata := NewMetadata("ungraded-assignments", nil)
+	metadata.Filters["state"] = "available"
+	rows := []*canvas.Assignment{{Name: "Essay"}, {Name: "Report"}}
+	if err := WriteJSON(&out, metadata, rows); err != nil {
+		t.Fatalf("WriteJSON() error: %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), "{\n  \"metadata\": {\n    \"report\": \"ungraded-assignments\",\n    \"accounts\": [],") {
+		t.Errorf("WriteJSON() is not indented with the metadata first:\n%s", out.String())
+	}
+
+	var export struct {
+		Metadata *Metadata                `json:"metadata"`
+		Rows     []map[string]interface{} `json:"rows"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &export); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid json: %v", err)
+	}
+	if export.Metadata.Rows != 2 || export.Metadata.Filters["state"] != "available" || export.Metadata.AppVersion != Version {
+		t.Errorf("WriteJSON() metadata %+v, want 2 rows, the state filter and version %s", export.Metadata, Version)
+	}
+	if _, err := time.Parse(time.RFC3339, export.Metadata.RunAt); err != nil || !strings.HasSuffix(export.Metadata.RunAt, "Z") {
+		t.Errorf("WriteJSON() run_at %q, want UTC ISO 8601", export.Metadata.RunAt)
+	}
+	if len(export.Rows) != 2 || export.Rows[1]["name"] != "Report" {
+		t.Errorf("WriteJSON() rows %v, want Essay and Report", export.Rows)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Course Name":   "course_name",
+		"due-at":        "due_at",
+		"CourseName":    "course_name",
+		"Gradebook URL": "gradebook_url",
+		"sis_user_id":   "sis_user_id",
+		"Student ID":    "student_id",
+	}
+
+	for key, want := range tests {
+		if got := snakeCase(key); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
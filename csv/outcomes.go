@@ -0,0 +1,26 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	"fmt"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/ninja-software/terror/v2"
+)
+
+func ExportOutcomeMastery(report []*canvas.OutcomeMastery, userSisID string) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%s-%s-outcome_mastery.csv", userSisID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&report, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	"fmt"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/ninja-software/terror/v2"
+)
+
+func ExportRubricReport(results []*canvas.RubricCriterionResult, courseID int) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%d-%s-rubric_criteria.csv", courseID, time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&results, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
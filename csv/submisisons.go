@@ -3,7 +3,6 @@ package csv
 import (
 	"canvas-desktop/canvas"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gocarina/gocsv"
@@ -12,7 +11,7 @@ import (
 
 func ExportUngradedSubmissions(submissions []*canvas.Submission, account *canvas.Account) error {
 	time := time.Now().Format("2006-01-02-15-04-05")
-	file, err := os.Create(fmt.Sprintf("%d-%s-ungraded_submissions.csv", account.ID, time))
+	file, err := createFile(fmt.Sprintf("%d-%s-ungraded_submissions.csv", account.ID, time))
 	if err != nil {
 		return err
 	}
@@ -25,3 +24,20 @@ func ExportUngradedSubmissions(submissions []*canvas.Submission, account *canvas
 
 	return nil
 }
+
+// name is the course or qualification the feedback was exported for
+func ExportFeedback(feedback []*canvas.SubmissionFeedback, name string) error {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	file, err := createFile(fmt.Sprintf("%s-%s-feedback.csv", canvas.ReplaceSpaceInStr(name, "_"), time))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = gocsv.MarshalFile(&feedback, file)
+	if err != nil {
+		return terror.Error(err, "cannot write rows to csv file")
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package csv
+
+import (
+	"canvas-desktop/canvas"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ninja-software/terror/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxSheet is one worksheet of a workbook, rows is a slice of pointers to a report row
+// whose csv tags name the columns, the same columns the CSV export has.
+type xlsxSheet struct {
+	name string
+	rows interface{}
+}
+
+type xlsxColumn struct {
+	header string
+	index  []int
+}
+
+type xlsxStyles struct {
+	header int
+	date   int
+	link   int
+}
+
+const maxColumnWidth = 60
+
+// ExportAssignmentsStatusXLSX writes one workbook with a sheet per campus and returns its path.
+func ExportAssignmentsStatusXLSX(assignments []*canvas.Assignment, account *canvas.Account) (string, error) {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	name := canvas.ReplaceSpaceInStr(account.Name, "_")
+
+	assignmentsByCampus := map[string][]*canvas.Assignment{
+		canvas.PerthCampus:    {},
+		canvas.AdelaideCampus: {},
+	}
+	for _, assignment := range assignments {
+		campus := canvas.CampusOf(assignment.Section)
+		assignmentsByCampus[campus] = append(assignmentsByCampus[campus], assignment)
+	}
+
+	return writeXLSX(fmt.Sprintf("%s-%s-assignments_status.xlsx", name, time), []*xlsxSheet{
+		{name: canvas.PerthCampus, rows: assignmentsByCampus[canvas.PerthCampus]},
+		{name: canvas.AdelaideCampus, rows: assignmentsByCampus[canvas.AdelaideCampus]},
+	})
+}
+
+func ExportUngradedSubmissionsXLSX(submissions []*canvas.Submission, account *canvas.Account) (string, error) {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	return writeXLSX(fmt.Sprintf("%d-%s-ungraded_submissions.xlsx", account.ID, time), []*xlsxSheet{
+		{name: "Ungraded Submissions", rows: submissions},
+	})
+}
+
+func ExportAssignmentsResultsXLSX(results []*canvas.AssignmentResult, userSisID string) (string, error) {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	return writeXLSX(fmt.Sprintf("%s-%s-assignment_results.xlsx", userSisID, time), []*xlsxSheet{
+		{name: "Assignment Results", rows: results},
+	})
+}
+
+func ExportEnrollmentsResultsXLSX(results []*canvas.EnrollmentResult, userSisID string) (string, error) {
+	time := time.Now().Format("2006-01-02-15-04-05")
+	return writeXLSX(fmt.Sprintf("%s-%s-enrollments_results.xlsx", userSisID, time), []*xlsxSheet{
+		{name: "Enrollment Results", rows: results},
+	})
+}
+
+func writeXLSX(name string, sheets []*xlsxSheet) (string, error) {
+	path, err := outputPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	styles, err := newXLSXStyles(file)
+	if err != nil {
+		return "", err
+	}
+
+	for i, sheet := range sheets {
+		// A new workbook comes with one empty sheet, the first sheet takes it over
+		if i == 0 {
+			err = file.SetSheetName(file.GetSheetName(0), sheet.name)
+		} else {
+			_, err = file.NewSheet(sheet.name)
+		}
+		if err != nil {
+			return "", terror.Error(err, fmt.Sprintf("cannot create sheet %s", sheet.name))
+		}
+
+		err = writeXLSXSheet(file, styles, sheet)
+		if err != nil {
+			return "", terror.Error(err, fmt.Sprintf("cannot write rows to sheet %s", sheet.name))
+		}
+	}
+
+	err = file.SaveAs(path)
+	if err != nil {
+		return "", terror.Error(err, "cannot save xlsx file")
+	}
+
+	return path, nil
+}
+
+func newXLSXStyles(file *excelize.File) (*xlsxStyles, error) {
+	header, err := file.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}},
+	})
+	if err != nil {
+		return nil, terror.Error(err, "cannot create header style")
+	}
+
+	dateFormat := "yyyy-mm-dd hh:mm"
+	date, err := file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return nil, terror.Error(err, "cannot create date style")
+	}
+
+	link, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "0563C1", Underline: "single"},
+	})
+	if err != nil {
+		return nil, terror.Error(err, "cannot create hyperlink style")
+	}
+
+	return &xlsxStyles{header: header, date: date, link: link}, nil
+}
+
+func writeXLSXSheet(file *excelize.File, styles *xlsxStyles, sheet *xlsxSheet) error {
+	rows := reflect.ValueOf(sheet.rows)
+	columns := xlsxColumns(rows.Type().Elem().Elem(), nil, "")
+	if len(columns) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := file.SetCellStr(sheet.name, cell, column.header); err != nil {
+			return err
+		}
+		widths[i] = utf8.RuneCountInString(column.header)
+	}
+
+	for r := 0; r < rows.Len(); r++ {
+		row := rows.Index(r).Elem()
+		for i, column := range columns {
+			cell, _ := excelize.CoordinatesToCellName(i+1, r+2)
+			text, err := setXLSXCell(file, styles, sheet.name, cell, column, row.FieldByIndex(column.index))
+			if err != nil {
+				return err
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(text))
+		}
+	}
+
+	for i, width := range widths {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		if err := file.SetColWidth(sheet.name, name, name, float64(min(width, maxColumnWidth)+2)); err != nil {
+			return err
+		}
+	}
+
+	first, _ := excelize.CoordinatesToCellName(1, 1)
+	last, _ := excelize.CoordinatesToCellName(len(columns), 1)
+	if err := file.SetCellStyle(sheet.name, first, last, styles.header); err != nil {
+		return err
+	}
+
+	err := file.SetPanes(sheet.name, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return err
+	}
+
+	last, _ = excelize.CoordinatesToCellName(len(columns), max(rows.Len()+1, 2))
+	return file.AutoFilter(sheet.name, first+":"+last, nil)
+}
+
+// xlsxColumns lists the fields written by gocsv, nested structs get their columns prefixed like "User.Name".
+func xlsxColumns(t reflect.Type, index []int, prefix string) []*xlsxColumn {
+	columns := []*xlsxColumn{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		header := strings.Split(field.Tag.Get("csv"), ",")[0]
+		if header == "-" || !field.IsExported() {
+			continue
+		}
+		if header == "" {
+			header = field.Name
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		if field.Type.Kind() == reflect.Struct {
+			columns = append(columns, xlsxColumns(field.Type, fieldIndex, prefix+header+".")...)
+			continue
+		}
+
+		columns = append(columns, &xlsxColumn{header: prefix + header, index: fieldIndex})
+	}
+
+	return columns
+}
+
+// setXLSXCell writes dates and numbers as typed cells and URLs as hyperlinks. It returns the
+// text shown in the cell for the column width.
+func setXLSXCell(file *excelize.File, styles *xlsxStyles, sheet string, cell string, column *xlsxColumn, value reflect.Value) (string, error) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), file.SetCellBool(sheet, cell, value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), file.SetCellInt(sheet, cell, int(value.Int()))
+	case reflect.Float32, reflect.Float64:
+		bitSize := value.Type().Bits()
+		text := strconv.FormatFloat(value.Float(), 'f', -1, bitSize)
+		return text, file.SetCellFloat(sheet, cell, value.Float(), -1, bitSize)
+	case reflect.String:
+	default:
+		text := fmt.Sprint(value.Interface())
+		return text, file.SetCellStr(sheet, cell, text)
+	}
+
+	text := value.String()
+	if text == "" {
+		return "", nil
+	}
+
+	if strings.HasSuffix(column.header, "URL") && strings.HasPrefix(text, "http") {
+		if err := file.SetCellStr(sheet, cell, text); err != nil {
+			return "", err
+		}
+		if err := file.SetCellHyperLink(sheet, cell, text, "External"); err != nil {
+			return "", err
+		}
+		return text, file.SetCellStyle(sheet, cell, cell, styles.link)
+	}
+
+	// Canvas timestamps are in UTC, Excel dates have no time zone so the local wall clock is written
+	if date, err := time.Parse(time.RFC3339, text); err == nil {
+		date = date.Local()
+		wallClock := time.Date(date.Year(), date.Month(), date.Day(), date.Hour(), date.Minute(), date.Second(), 0, time.UTC)
+		if err := file.SetCellValue(sheet, cell, wallClock); err != nil {
+			return "", err
+		}
+		return "yyyy-mm-dd hh:mm", file.SetCellStyle(sheet, cell, cell, styles.date)
+	}
+
+	return text, file.SetCellStr(sheet, cell, text)
+}
@@ -0,0 +1,110 @@
+package email
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// Config is read from a JSON file so SMTP details and recipient lists don't live in the code.
+//
+//	{
+//	  "profiles": {
+//	    "monday": {
+//	      "smtp": {"host": "smtp.example.com", "port": 587, "username": "reports", "password_env": "SMTP_PASSWORD", "from": "reports@example.com"},
+//	      "subject": "Assignments status {{.Date}}",
+//	      "body": "Hi {{.Recipient.Name}},\n\nAttached are this week's files.",
+//	      "recipients": [{"name": "Perth manager", "email": "perth@example.com", "segments": ["PERTH"]}]
+//	    }
+//	  }
+//	}
+type Config struct {
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Name of an environment variable with the password, takes precedence over Password
+	PasswordEnv string `json:"password_env"`
+	From        string `json:"from"`
+}
+
+// Recipient only gets the attachments of their segments, e.g. a campus. No segments means every attachment.
+type Recipient struct {
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Segments []string `json:"segments"`
+}
+
+// Profile is one kind of delivery, Subject and Body are text/template executed with TemplateData.
+type Profile struct {
+	Name       string       `json:"-"`
+	SMTP       SMTPConfig   `json:"smtp"`
+	Subject    string       `json:"subject"`
+	Body       string       `json:"body"`
+	Recipients []*Recipient `json:"recipients"`
+}
+
+// DefaultConfigPath is next to the write journal in the user config directory.
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "canvas-email.json"
+	}
+
+	return filepath.Join(dir, "canvas-desktop", "email.json")
+}
+
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, terror.Error(err, fmt.Sprintf("cannot read email config: %s", path))
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal email config")
+	}
+
+	for name, profile := range config.Profiles {
+		profile.Name = name
+		if profile.SMTP.Port == 0 {
+			profile.SMTP.Port = 587
+		}
+		if profile.SMTP.PasswordEnv != "" {
+			profile.SMTP.Password = os.Getenv(profile.SMTP.PasswordEnv)
+		}
+	}
+
+	return config, nil
+}
+
+func (config *Config) Profile(name string) (*Profile, error) {
+	profile := config.Profiles[name]
+	if profile == nil {
+		return nil, terror.Error(fmt.Errorf("unknown profile: %s", name), "email profile not found in config")
+	}
+
+	if profile.SMTP.Host == "" || profile.SMTP.From == "" {
+		return nil, terror.Error(fmt.Errorf("profile %s is missing smtp host or from", name), "email profile is incomplete")
+	}
+
+	return profile, nil
+}
+
+func (config *Config) ProfileNames() []string {
+	names := []string{}
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,214 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// Attachment is a generated export, Segment is empty for files every recipient gets.
+type Attachment struct {
+	Path    string
+	Segment string
+}
+
+// TemplateData is what the subject and body templates are executed with.
+// Data is whatever the caller wants to add, e.g. counts for a digest.
+type TemplateData struct {
+	Profile     string
+	Recipient   *Recipient
+	Date        string
+	Attachments []string
+	Data        interface{}
+}
+
+type Delivery struct {
+	Name        string `json:"name" csv:"Name"`
+	Email       string `json:"email" csv:"Email"`
+	Attachments int    `json:"attachments" csv:"Attachments"`
+	Sent        bool   `json:"sent" csv:"Sent"`
+	Error       string `json:"error" csv:"Error"`
+}
+
+// SendReports emails each recipient of the profile the attachments of their segments.
+// Recipients with no matching attachments are skipped, a failed recipient doesn't stop the others.
+func SendReports(profile *Profile, attachments []*Attachment, data interface{}) ([]*Delivery, error) {
+	subject, err := template.New("subject").Parse(profile.Subject)
+	if err != nil {
+		return nil, terror.Error(err, "cannot parse subject template")
+	}
+
+	body, err := template.New("body").Parse(profile.Body)
+	if err != nil {
+		return nil, terror.Error(err, "cannot parse body template")
+	}
+
+	deliveries := []*Delivery{}
+	for _, recipient := range profile.Recipients {
+		delivery := &Delivery{
+			Name:  recipient.Name,
+			Email: recipient.Email,
+		}
+		deliveries = append(deliveries, delivery)
+
+		files := recipientAttachments(recipient, attachments)
+		if len(attachments) > 0 && len(files) == 0 {
+			delivery.Error = "no attachments for the recipient's segments"
+			continue
+		}
+		delivery.Attachments = len(files)
+
+		templateData := &TemplateData{
+			Profile:   profile.Name,
+			Recipient: recipient,
+			Date:      time.Now().Format("2006-01-02"),
+			Data:      data,
+		}
+		for _, file := range files {
+			templateData.Attachments = append(templateData.Attachments, filepath.Base(file.Path))
+		}
+
+		subjectText := &bytes.Buffer{}
+		if err := subject.Execute(subjectText, templateData); err != nil {
+			return deliveries, terror.Error(err, "cannot render subject template")
+		}
+
+		bodyText := &bytes.Buffer{}
+		if err := body.Execute(bodyText, templateData); err != nil {
+			return deliveries, terror.Error(err, "cannot render body template")
+		}
+
+		message, err := buildMessage(profile.SMTP.From, recipient, subjectText.String(), bodyText.String(), files)
+		if err != nil {
+			return deliveries, err
+		}
+
+		if err := send(&profile.SMTP, recipient.Email, message); err != nil {
+			delivery.Error = err.Error()
+			continue
+		}
+		delivery.Sent = true
+	}
+
+	return deliveries, nil
+}
+
+// SendTest sends a short message with no attachments to every recipient of the profile.
+func SendTest(profile *Profile) ([]*Delivery, error) {
+	test := *profile
+	test.Subject = "Canvas reports test: " + profile.Name
+	test.Body = "Hi {{.Recipient.Name}},\n\nThis is a test of the {{.Profile}} email profile sent on {{.Date}}.\nSegments: {{range .Recipient.Segments}}{{.}} {{else}}all{{end}}\n"
+
+	return SendReports(&test, nil, nil)
+}
+
+func recipientAttachments(recipient *Recipient, attachments []*Attachment) []*Attachment {
+	files := []*Attachment{}
+	for _, attachment := range attachments {
+		if attachment.Segment == "" || len(recipient.Segments) == 0 || slices.Contains(recipient.Segments, attachment.Segment) {
+			files = append(files, attachment)
+		}
+	}
+
+	return files
+}
+
+func buildMessage(from string, recipient *Recipient, subject string, body string, attachments []*Attachment) ([]byte, error) {
+	message := &bytes.Buffer{}
+	writer := multipart.NewWriter(message)
+
+	to := recipient.Email
+	if recipient.Name != "" {
+		to = fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", recipient.Name), recipient.Email)
+	}
+
+	fmt.Fprintf(message, "From: %s\r\n", from)
+	fmt.Fprintf(message, "To: %s\r\n", to)
+	fmt.Fprintf(message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(message, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, terror.Error(err, "cannot create message body")
+	}
+	text := quotedprintable.NewWriter(part)
+	if _, err := text.Write([]byte(body)); err != nil {
+		return nil, terror.Error(err, "cannot write message body")
+	}
+	text.Close()
+
+	for _, attachment := range attachments {
+		content, err := os.ReadFile(attachment.Path)
+		if err != nil {
+			return nil, terror.Error(err, fmt.Sprintf("cannot read attachment: %s", attachment.Path))
+		}
+
+		name := filepath.Base(attachment.Path)
+		contentType := mime.TypeByExtension(filepath.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", contentType, name)},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+		})
+		if err != nil {
+			return nil, terror.Error(err, "cannot create attachment part")
+		}
+
+		// Lines of an encoded part must not be longer than 76 characters
+		encoded := base64.StdEncoding.EncodeToString(content)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, terror.Error(err, "cannot close message")
+	}
+
+	return message.Bytes(), nil
+}
+
+// send uses STARTTLS when the server offers it. Without a username no auth is attempted,
+// which is what a local SMTP stand-in like MailHog expects.
+func send(config *SMTPConfig, to string, message []byte) error {
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return terror.Error(err, fmt.Sprintf("invalid from address: %s", config.From))
+	}
+
+	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	if err := smtp.SendMail(addr, auth, from.Address, []string{to}, message); err != nil {
+		return terror.Error(err, fmt.Sprintf("cannot send email to %s", to))
+	}
+
+	return nil
+}
@@ -1,5 +1,7 @@
 import { useState } from "react";
 import "./App.css";
+import GradeImport from "./components/gradeImport";
+import StudentAssessments from "./components/studentAssessments";
 import UngradedSubmissions from "./components/ungradedSubmissions";
 import { Export } from "./types";
 
@@ -40,6 +42,9 @@ function App() {
           <option value={Export.StudentAssessments} style={{ padding: 10 }}>
             Export student assessments
           </option>
+          <option value={Export.GradeImport} style={{ padding: 10 }}>
+            Import grades from CSV
+          </option>
         </select>
       </div>
       {exportItem === Export.UngradedAssignments && (
@@ -48,6 +53,18 @@ function App() {
           changeInProgress={changeInProgres}
         />
       )}
+      {exportItem === Export.StudentAssessments && (
+        <StudentAssessments
+          inProgress={inProgress}
+          changeInProgress={changeInProgres}
+        />
+      )}
+      {exportItem === Export.GradeImport && (
+        <GradeImport
+          inProgress={inProgress}
+          changeInProgress={changeInProgres}
+        />
+      )}
     </div>
   );
 }
@@ -0,0 +1,193 @@
+import { useState } from "react";
+import {
+  ApplyGradeImport,
+  PreviewGradeImport,
+} from "../../wailsjs/go/canvas/Controller";
+import {
+  ExportGradeImportPlan,
+  ReadGradeImport,
+  SelectCSVFile,
+} from "../../wailsjs/go/main/App";
+import { canvas } from "../../wailsjs/go/models";
+import "../App.css";
+
+interface GradeImportProps {
+  inProgress: boolean;
+  changeInProgress: (val: boolean) => void;
+}
+
+export default function GradeImport({
+  inProgress,
+  changeInProgress,
+}: GradeImportProps) {
+  const [courseID, setCourseID] = useState<number>(0);
+  const [filename, setFilename] = useState("");
+  const [plan, setPlan] = useState<canvas.GradeImportPlan | null>(null);
+  const [errorMsg, setErrorMsg] = useState("");
+  const [successMsg, setSuccessMsg] = useState("");
+
+  const pending = plan
+    ? plan.changes.filter(
+        (change) =>
+          change.status === canvas.GradeChangeStatus.NEW ||
+          change.status === canvas.GradeChangeStatus.CHANGED
+      )
+    : [];
+
+  const handleSelectFile = async () => {
+    const _filename = await SelectCSVFile("Select grades CSV");
+    if (_filename) {
+      setFilename(_filename);
+      setPlan(null);
+    }
+  };
+
+  const handlePreview = async (e: React.FormEvent<HTMLFormElement>) => {
+    e.preventDefault();
+    changeInProgress(true);
+    setSuccessMsg("");
+    setErrorMsg("");
+    setPlan(null);
+
+    try {
+      const rows = await ReadGradeImport(filename);
+      const _plan = await PreviewGradeImport(courseID, rows);
+      setPlan(_plan);
+    } catch (err: any) {
+      setErrorMsg(err);
+    } finally {
+      changeInProgress(false);
+    }
+  };
+
+  const handleApply = async () => {
+    if (!plan) {
+      return;
+    }
+
+    const confirmed = window.confirm(
+      `Apply ${pending.length} grade changes to ${plan.course_name}?`
+    );
+    if (!confirmed) {
+      return;
+    }
+
+    changeInProgress(true);
+    setSuccessMsg("");
+    setErrorMsg("");
+
+    try {
+      const results = await ApplyGradeImport(plan, true);
+      const mismatches = results.filter((result) => result.status !== "updated");
+      setSuccessMsg(
+        `Applied ${results.length - mismatches.length} grade changes, ${
+          mismatches.length
+        } need checking.`
+      );
+      setPlan(null);
+    } catch (err: any) {
+      setErrorMsg(err);
+    } finally {
+      changeInProgress(false);
+    }
+  };
+
+  return (
+    <div>
+      <div style={{ marginBottom: "0.5em" }}>
+        <label>Import grades from CSV (Student ID, Assignment, Grade)</label>
+      </div>
+      <div style={{ maxWidth: "40rem", margin: "0 auto", padding: "0 1rem" }}>
+        <form
+          onSubmit={handlePreview}
+          style={{
+            display: "flex",
+            flexDirection: "column",
+            justifyContent: "center",
+            alignItems: "center",
+            maxWidth: "40rem",
+            gap: "1em",
+          }}
+        >
+          <div>
+            <label>Course ID: </label>
+            <input
+              type="number"
+              value={courseID || ""}
+              onChange={(e) => setCourseID(Number(e.target.value))}
+              disabled={inProgress}
+            />
+          </div>
+          <div>
+            <button type="button" onClick={handleSelectFile} disabled={inProgress}>
+              Choose CSV
+            </button>
+            <span> {filename}</span>
+          </div>
+          <button
+            type="submit"
+            disabled={inProgress || !courseID || !filename}
+          >
+            Preview
+          </button>
+        </form>
+      </div>
+      {plan && (
+        <div style={{ marginTop: "1em" }}>
+          <div>
+            {plan.course_name}: {plan.summary["new"] ?? 0} new,{" "}
+            {plan.summary["changed"] ?? 0} changed,{" "}
+            {plan.summary["unchanged"] ?? 0} unchanged,{" "}
+            {plan.summary["unknown_student"] ?? 0} unknown student,{" "}
+            {plan.summary["unknown_assignment"] ?? 0} unknown assignment,{" "}
+            {plan.summary["conflict"] ?? 0} conflict
+          </div>
+          <table style={{ margin: "1em auto", borderCollapse: "collapse" }}>
+            <thead>
+              <tr>
+                <th>Row</th>
+                <th>Student</th>
+                <th>Assignment</th>
+                <th>Current</th>
+                <th>New</th>
+                <th>Status</th>
+              </tr>
+            </thead>
+            <tbody>
+              {plan.changes.map((change) => (
+                <tr key={change.row}>
+                  <td>{change.row}</td>
+                  <td>
+                    {change.student_id} {change.student_name}
+                  </td>
+                  <td>{change.assignment}</td>
+                  <td>{change.current_grade}</td>
+                  <td>{change.new_grade}</td>
+                  <td title={change.status_details}>{change.status}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+          <div style={{ display: "flex", gap: "1em", justifyContent: "center" }}>
+            <button
+              onClick={() => ExportGradeImportPlan(plan)}
+              disabled={inProgress}
+            >
+              Save diff as CSV
+            </button>
+            <button
+              onClick={handleApply}
+              disabled={inProgress || pending.length === 0}
+            >
+              Apply {pending.length} changes
+            </button>
+          </div>
+        </div>
+      )}
+      <div style={{ marginTop: "0.5em" }}>
+        {errorMsg && <span style={{ color: "#ef5350" }}> {errorMsg}</span>}
+        {successMsg && <span>{successMsg}</span>}
+      </div>
+    </div>
+  );
+}
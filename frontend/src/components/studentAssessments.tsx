@@ -0,0 +1,228 @@
+import { useEffect, useState } from "react";
+import { GetRosterResults } from "../../wailsjs/go/canvas/Controller";
+import {
+  ExportRosterResults,
+  ReadRoster,
+  SelectCSVFile,
+} from "../../wailsjs/go/main/App";
+import { canvas } from "../../wailsjs/go/models";
+import { EventsOn } from "../../wailsjs/runtime/runtime";
+import "../App.css";
+import { Qualifications } from "../constant";
+
+interface StudentAssessmentsProps {
+  inProgress: boolean;
+  changeInProgress: (val: boolean) => void;
+}
+
+export default function StudentAssessments({
+  inProgress,
+  changeInProgress,
+}: StudentAssessmentsProps) {
+  const [studentIDs, setStudentIDs] = useState("");
+  const [identifierType, setIdentifierType] = useState<canvas.UserIdentifierType>(
+    canvas.UserIdentifierType.SIS_USER_ID
+  );
+  // Account searched for emails, 0 skips email lookups
+  const [accountID, setAccountID] = useState<number>(0);
+  const [perStudent, setPerStudent] = useState(true);
+  const [progress, setProgress] = useState<canvas.TaskProgress | null>(null);
+  const [report, setReport] = useState<canvas.StudentAssessmentsReport | null>(
+    null
+  );
+  const [errorMsg, setErrorMsg] = useState("");
+  const [successMsg, setSuccessMsg] = useState("");
+
+  useEffect(() => {
+    return EventsOn("progress", (_progress: canvas.TaskProgress) => {
+      if (_progress.task === "roster-results") {
+        setProgress(_progress);
+      }
+    });
+  }, []);
+
+  const ids = studentIDs
+    .split(/[\s,;]+/)
+    .map((id) => id.trim())
+    .filter((id) => id !== "");
+
+  const handleSelectRoster = async () => {
+    setErrorMsg("");
+    try {
+      const filename = await SelectCSVFile("Select class list CSV");
+      if (filename) {
+        const roster = await ReadRoster(filename);
+        setStudentIDs(roster.join("\n"));
+      }
+    } catch (err: any) {
+      setErrorMsg(err);
+    }
+  };
+
+  const handleSubmit = async (e: React.FormEvent<HTMLFormElement>) => {
+    e.preventDefault();
+    changeInProgress(true);
+    setSuccessMsg("");
+    setErrorMsg("");
+    setReport(null);
+    setProgress(null);
+
+    try {
+      const _report = await GetRosterResults(accountID, identifierType, ids);
+      setReport(_report);
+      const path = await ExportRosterResults(_report, perStudent);
+      setSuccessMsg(
+        `Exported ${_report.students.length} students to ${path}, ${_report.not_found.length} not found` +
+          (perStudent ? `, per student files in ${path.replace(/\.csv$/, "")}` : "")
+      );
+    } catch (err: any) {
+      setErrorMsg(err);
+    } finally {
+      changeInProgress(false);
+    }
+  };
+
+  return (
+    <div>
+      <div style={{ marginBottom: "0.5em" }}>
+        <label>Export enrollment and assignment results of students</label>
+      </div>
+      <div style={{ maxWidth: "40rem", margin: "0 auto", padding: "0 1rem" }}>
+        <form
+          onSubmit={handleSubmit}
+          style={{
+            display: "flex",
+            flexDirection: "column",
+            justifyContent: "center",
+            alignItems: "center",
+            maxWidth: "40rem",
+            gap: "1em",
+          }}
+        >
+          <textarea
+            rows={6}
+            cols={40}
+            placeholder="Student IDs, logins or emails, one per line or separated by commas"
+            value={studentIDs}
+            onChange={(e) => setStudentIDs(e.target.value)}
+            disabled={inProgress}
+          />
+          <div style={{ display: "flex", gap: "1em", alignItems: "center" }}>
+            <button
+              type="button"
+              onClick={handleSelectRoster}
+              disabled={inProgress}
+            >
+              Load class list CSV
+            </button>
+            <select
+              value={identifierType}
+              onChange={(e) =>
+                setIdentifierType(e.target.value as canvas.UserIdentifierType)
+              }
+              disabled={inProgress}
+            >
+              <option value={canvas.UserIdentifierType.SIS_USER_ID}>
+                Student IDs
+              </option>
+              <option value={canvas.UserIdentifierType.LOGIN_ID}>Logins</option>
+              <option value={canvas.UserIdentifierType.EMAIL}>Emails</option>
+              <option value={canvas.UserIdentifierType.AUTO}>
+                Student IDs, logins or emails
+              </option>
+            </select>
+            <label>
+              <input
+                type="checkbox"
+                checked={perStudent}
+                onChange={(e) => setPerStudent(e.target.checked)}
+                disabled={inProgress}
+              />
+              File per student
+            </label>
+          </div>
+          {(identifierType === canvas.UserIdentifierType.EMAIL ||
+            identifierType === canvas.UserIdentifierType.AUTO) && (
+            <div>
+              <label>Search emails in: </label>
+              <select
+                value={accountID}
+                onChange={(e) => setAccountID(Number(e.target.value))}
+                disabled={inProgress}
+              >
+                <option value={0}>
+                  {identifierType === canvas.UserIdentifierType.EMAIL
+                    ? "Select a qualification"
+                    : "Don't search emails"}
+                </option>
+                {Qualifications.map((qualification) => (
+                  <option
+                    key={qualification.AccountID}
+                    value={qualification.AccountID}
+                  >
+                    {qualification.Name}
+                  </option>
+                ))}
+              </select>
+            </div>
+          )}
+          <button
+            type="submit"
+            disabled={
+              inProgress ||
+              ids.length === 0 ||
+              (identifierType === canvas.UserIdentifierType.EMAIL &&
+                accountID === 0)
+            }
+          >
+            Start
+          </button>
+        </form>
+      </div>
+      {inProgress && progress && (
+        <div style={{ marginTop: "1em" }}>
+          <progress value={progress.done} max={progress.total || 1} />
+          <div>
+            {progress.done}/{progress.total} {progress.message}
+          </div>
+        </div>
+      )}
+      {report && (
+        <table style={{ margin: "1em auto", borderCollapse: "collapse" }}>
+          <thead>
+            <tr>
+              <th>Student</th>
+              <th>Courses</th>
+              <th>Assignments</th>
+              <th>Status</th>
+            </tr>
+          </thead>
+          <tbody>
+            {report.students.map((student) => (
+              <tr key={student.identifier}>
+                <td>
+                  {student.identifier} {student.user?.name}
+                </td>
+                <td>{student.enrollments?.length ?? 0}</td>
+                <td>{student.assignments?.length ?? 0}</td>
+                <td>{student.error || "ok"}</td>
+              </tr>
+            ))}
+            {report.not_found.map((user) => (
+              <tr key={user.identifier}>
+                <td>{user.identifier}</td>
+                <td />
+                <td />
+                <td>not found</td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      )}
+      <div style={{ marginTop: "0.5em" }}>
+        {errorMsg && <span style={{ color: "#ef5350" }}> {errorMsg}</span>}
+        {successMsg && <span>{successMsg}</span>}
+      </div>
+    </div>
+  );
+}
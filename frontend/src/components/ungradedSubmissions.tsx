@@ -1,10 +1,14 @@
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import {
   GetAccountByID,
   GetAssignmentsByCourse,
   GetCoursesByAccount,
+  GetTermsByAccount,
 } from "../../wailsjs/go/canvas/APIClient";
-import { ExportAssignmentsStatus } from "../../wailsjs/go/main/App";
+import {
+  ExportAssignmentsStatus,
+  ExportAssignmentsStatusXLSX,
+} from "../../wailsjs/go/main/App";
 import { canvas } from "../../wailsjs/go/models";
 import "../App.css";
 import { Qualifications } from "../constant";
@@ -25,6 +29,18 @@ export default function UngradedSubmissions({
   const [errorMsg, setErrorMsg] = useState("");
   const [successMsg, setSuccessMsg] = useState("");
   const [progress, setProgress] = useState(0);
+  const [terms, setTerms] = useState<canvas.Term[]>([]);
+  const [termID, setTermID] = useState<number>(0); // 0 for all terms
+  const [activeOnly, setActiveOnly] = useState(true);
+  const [format, setFormat] = useState<"csv" | "xlsx">("csv");
+
+  useEffect(() => {
+    // Terms belong to the root account, so any qualification will do
+    GetAccountByID(qualifications[0].AccountID)
+      .then((account) => GetTermsByAccount(account))
+      .then((terms) => setTerms(terms))
+      .catch((err) => setErrorMsg(err));
+  }, []);
 
   const handleSubmit = async (e: React.FormEvent<HTMLFormElement>) => {
     e.preventDefault();
@@ -37,10 +53,15 @@ export default function UngradedSubmissions({
       const account = await GetAccountByID(accountID);
       const courses = await GetCoursesByAccount(
         account,
-        canvas.CourseEnrollmentType.STUDENT
+        new canvas.CourseQueryOptions({
+          enrollment_types: [canvas.CourseEnrollmentType.STUDENT],
+          // Leave out concluded and unpublished courses
+          states: activeOnly ? [canvas.CourseState.AVAILABLE] : [],
+          enrollment_term_id: termID,
+        })
       );
       let completedCourses = 0;
-      const totalProgress = courses.length + 1; // One for CSV export operation
+      const totalProgress = courses.length + 1; // One for the export operation
 
       for (let i = 0; i < courses.length; i++) {
         const _assignments = await GetAssignmentsByCourse(
@@ -53,9 +74,14 @@ export default function UngradedSubmissions({
         setProgress((completedCourses / totalProgress) * 100);
       }
 
-      await ExportAssignmentsStatus(assignments, account);
+      if (format === "xlsx") {
+        const path = await ExportAssignmentsStatusXLSX(assignments, account);
+        setSuccessMsg(`Successfully created ${path}.`);
+      } else {
+        await ExportAssignmentsStatus(assignments, account);
+        setSuccessMsg("Successfully created 2 CSV files in currrent folder.");
+      }
       setProgress(100);
-      setSuccessMsg("Successfully created 2 CSV files in currrent folder.");
     } catch (err: any) {
       // setErrorMsg("Something went wrong.");
       setErrorMsg(err);
@@ -100,6 +126,42 @@ export default function UngradedSubmissions({
               ))}
             </select>
           </div>
+          <div>
+            <label>Select a term: </label>
+            <select
+              onChange={(e) => setTermID(Number(e.target.value))}
+              disabled={inProgress}
+            >
+              <option value={0}>All terms</option>
+              {terms.map((term) => (
+                <option key={term.id} value={term.id}>
+                  {term.name}
+                </option>
+              ))}
+            </select>
+          </div>
+          <div>
+            <label>
+              <input
+                type="checkbox"
+                checked={activeOnly}
+                onChange={(e) => setActiveOnly(e.target.checked)}
+                disabled={inProgress}
+              />
+              Only published courses that haven't concluded
+            </label>
+          </div>
+          <div>
+            <label>Export as: </label>
+            <select
+              value={format}
+              onChange={(e) => setFormat(e.target.value as "csv" | "xlsx")}
+              disabled={inProgress}
+            >
+              <option value="csv">CSV file per campus</option>
+              <option value="xlsx">Excel workbook, sheet per campus</option>
+            </select>
+          </div>
           <button type="submit" disabled={inProgress}>
             Start
           </button>
@@ -1,6 +1,7 @@
 export enum Export {
   UngradedAssignments = "UNGRADED_ASSIGNMENTS",
   StudentAssessments = "STUDENT_ASSESSMENTS",
+  GradeImport = "GRADE_IMPORT",
 }
 
 export interface Qualification {
@@ -9,7 +9,9 @@ export function GetAccountByID(arg1:number):Promise<canvas.Account>;
 
 export function GetAllEnrollmentsResultsByUserID(arg1:number):Promise<Array<canvas.EnrollmentResult>>;
 
-export function GetAssignmentsByAccount(arg1:canvas.Account,arg2:canvas.AssignmentBucket):Promise<Array<canvas.Assignment>>;
+export function GetAssignmentGroupsByCourseID(arg1:number):Promise<Array<canvas.AssignmentGroup>>;
+
+export function GetAssignmentsByAccount(arg1:canvas.Account,arg2:canvas.AssignmentBucket,arg3:canvas.CourseQueryOptions):Promise<Array<canvas.Assignment>>;
 
 export function GetAssignmentsByCourse(arg1:canvas.Course,arg2:canvas.AssignmentBucket):Promise<Array<canvas.Assignment>>;
 
@@ -17,22 +19,36 @@ export function GetAssignmentsResultsByUser(arg1:canvas.User):Promise<Array<canv
 
 export function GetCourseByID(arg1:number):Promise<canvas.Course>;
 
-export function GetCoursesByAccount(arg1:canvas.Account,arg2:canvas.CourseEnrollmentType):Promise<Array<canvas.Course>>;
+export function GetCoursesByAccount(arg1:canvas.Account,arg2:canvas.CourseQueryOptions):Promise<Array<canvas.Course>>;
 
-export function GetCoursesByAccountID(arg1:number,arg2:canvas.CourseEnrollmentType):Promise<Array<canvas.Course>>;
+export function GetCoursesByAccountID(arg1:number,arg2:canvas.CourseQueryOptions):Promise<Array<canvas.Course>>;
 
 export function GetEnrollmentsBySectionID(arg1:number,arg2:Array<canvas.EnrollmentType>):Promise<Array<canvas.Enrollment>>;
 
 export function GetEnrollmentsByUserID(arg1:number):Promise<Array<canvas.Enrollment>>;
 
+export function GetJournalOperations():Promise<Array<canvas.JournalOperation>>;
+
+export function GetQuizSubmissionByStudent(arg1:number,arg2:number,arg3:number):Promise<canvas.QuizSubmission>;
+
 export function GetSectionByID(arg1:number):Promise<canvas.Section>;
 
 export function GetSectionsByCourseID(arg1:number):Promise<Array<canvas.Section>>;
 
+export function GetStudentSummariesByCourseID(arg1:number):Promise<Array<canvas.StudentSummary>>;
+
 export function GetSubmissions(arg1:number,arg2:number):Promise<Array<canvas.Submission>>;
 
-export function GetUngradedSubmissionsByAccount(arg1:canvas.Account):Promise<Array<canvas.Submission>>;
+export function GetTermByID(arg1:number,arg2:number):Promise<canvas.Term>;
+
+export function GetTermsByAccount(arg1:canvas.Account):Promise<Array<canvas.Term>>;
+
+export function GetTermsByAccountID(arg1:number):Promise<Array<canvas.Term>>;
+
+export function GetUngradedSubmissionsByAccount(arg1:canvas.Account,arg2:canvas.CourseQueryOptions):Promise<Array<canvas.Submission>>;
 
 export function GetUserBySisID(arg1:string):Promise<canvas.User>;
 
 export function LongRunningSleepFunc(arg1:context.Context):Promise<void>;
+
+export function RollbackOperation(arg1:string):Promise<canvas.RollbackReport>;
@@ -14,8 +14,12 @@ export function GetAllEnrollmentsResultsByUserID(arg1) {
   return window['go']['canvas']['APIClient']['GetAllEnrollmentsResultsByUserID'](arg1);
 }
 
-export function GetAssignmentsByAccount(arg1, arg2) {
-  return window['go']['canvas']['APIClient']['GetAssignmentsByAccount'](arg1, arg2);
+export function GetAssignmentGroupsByCourseID(arg1) {
+  return window['go']['canvas']['APIClient']['GetAssignmentGroupsByCourseID'](arg1);
+}
+
+export function GetAssignmentsByAccount(arg1, arg2, arg3) {
+  return window['go']['canvas']['APIClient']['GetAssignmentsByAccount'](arg1, arg2, arg3);
 }
 
 export function GetAssignmentsByCourse(arg1, arg2) {
@@ -46,6 +50,14 @@ export function GetEnrollmentsByUserID(arg1) {
   return window['go']['canvas']['APIClient']['GetEnrollmentsByUserID'](arg1);
 }
 
+export function GetJournalOperations() {
+  return window['go']['canvas']['APIClient']['GetJournalOperations']();
+}
+
+export function GetQuizSubmissionByStudent(arg1, arg2, arg3) {
+  return window['go']['canvas']['APIClient']['GetQuizSubmissionByStudent'](arg1, arg2, arg3);
+}
+
 export function GetSectionByID(arg1) {
   return window['go']['canvas']['APIClient']['GetSectionByID'](arg1);
 }
@@ -54,12 +66,28 @@ export function GetSectionsByCourseID(arg1) {
   return window['go']['canvas']['APIClient']['GetSectionsByCourseID'](arg1);
 }
 
+export function GetStudentSummariesByCourseID(arg1) {
+  return window['go']['canvas']['APIClient']['GetStudentSummariesByCourseID'](arg1);
+}
+
 export function GetSubmissions(arg1, arg2) {
   return window['go']['canvas']['APIClient']['GetSubmissions'](arg1, arg2);
 }
 
-export function GetUngradedSubmissionsByAccount(arg1) {
-  return window['go']['canvas']['APIClient']['GetUngradedSubmissionsByAccount'](arg1);
+export function GetTermByID(arg1, arg2) {
+  return window['go']['canvas']['APIClient']['GetTermByID'](arg1, arg2);
+}
+
+export function GetTermsByAccount(arg1) {
+  return window['go']['canvas']['APIClient']['GetTermsByAccount'](arg1);
+}
+
+export function GetTermsByAccountID(arg1) {
+  return window['go']['canvas']['APIClient']['GetTermsByAccountID'](arg1);
+}
+
+export function GetUngradedSubmissionsByAccount(arg1, arg2) {
+  return window['go']['canvas']['APIClient']['GetUngradedSubmissionsByAccount'](arg1, arg2);
 }
 
 export function GetUserBySisID(arg1) {
@@ -69,3 +97,7 @@ export function GetUserBySisID(arg1) {
 export function LongRunningSleepFunc(arg1) {
   return window['go']['canvas']['APIClient']['LongRunningSleepFunc'](arg1);
 }
+
+export function RollbackOperation(arg1) {
+  return window['go']['canvas']['APIClient']['RollbackOperation'](arg1);
+}
@@ -2,4 +2,32 @@
 // This file is automatically generated. DO NOT EDIT
 import {canvas} from '../models';
 
+export function ApplyGradeImport(arg1:canvas.GradeImportPlan,arg2:boolean):Promise<Array<canvas.GradeUpdateResult>>;
+
+export function GetCoursesByQualification(arg1:canvas.Qualification,arg2:canvas.CourseQueryOptions):Promise<Array<canvas.Course>>;
+
+export function GetFeedbackByCourse(arg1:number):Promise<Array<canvas.SubmissionFeedback>>;
+
+export function GetFeedbackByQualification(arg1:canvas.Qualification,arg2:canvas.CourseQueryOptions):Promise<Array<canvas.SubmissionFeedback>>;
+
+export function GetGradeBreakdownsByCourse(arg1:number):Promise<Array<canvas.GradeBreakdown>>;
+
+export function GetGradeChangeAudit(arg1:canvas.GradeChangeScope,arg2:number,arg3:string,arg4:string):Promise<Array<canvas.GradeChangeRecord>>;
+
+export function GetInactiveStudentsByAccountID(arg1:number,arg2:canvas.CourseQueryOptions,arg3:canvas.InactivityCriteria):Promise<Array<canvas.InactiveStudent>>;
+
+export function GetMasteryReportByStudent(arg1:canvas.Qualification,arg2:canvas.User):Promise<Array<canvas.OutcomeMastery>>;
+
 export function GetQualifications():Promise<Array<canvas.Qualification>>;
+
+export function GetRosterResults(arg1:number,arg2:canvas.UserIdentifierType,arg3:Array<string>):Promise<Promise<canvas.StudentAssessmentsReport>>;
+
+export function GetRubricReportByCourse(arg1:number):Promise<Array<canvas.RubricCriterionResult>>;
+
+export function GetStudentEngagementByAccountID(arg1:number,arg2:canvas.CourseQueryOptions,arg3:boolean):Promise<Array<canvas.StudentEngagement>>;
+
+export function PreviewGradeImport(arg1:number,arg2:Array<canvas.GradeImportRow>):Promise<canvas.GradeImportPlan>;
+
+export function PreviewGradingNudges(arg1:Array<canvas.Assignment>,arg2:string,arg3:string):Promise<Promise<canvas.GradingNudgePlan>>;
+
+export function SendGradingNudges(arg1:canvas.GradingNudgePlan):Promise<Promise<Array<canvas.GradingNudge>>>;
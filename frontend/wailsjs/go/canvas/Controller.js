@@ -2,6 +2,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApplyGradeImport(arg1, arg2) {
+  return window['go']['canvas']['Controller']['ApplyGradeImport'](arg1, arg2);
+}
+
+export function GetCoursesByQualification(arg1, arg2) {
+  return window['go']['canvas']['Controller']['GetCoursesByQualification'](arg1, arg2);
+}
+
+export function GetFeedbackByCourse(arg1) {
+  return window['go']['canvas']['Controller']['GetFeedbackByCourse'](arg1);
+}
+
+export function GetFeedbackByQualification(arg1, arg2) {
+  return window['go']['canvas']['Controller']['GetFeedbackByQualification'](arg1, arg2);
+}
+
+export function GetGradeBreakdownsByCourse(arg1) {
+  return window['go']['canvas']['Controller']['GetGradeBreakdownsByCourse'](arg1);
+}
+
+export function GetGradeChangeAudit(arg1, arg2, arg3, arg4) {
+  return window['go']['canvas']['Controller']['GetGradeChangeAudit'](arg1, arg2, arg3, arg4);
+}
+
+export function GetInactiveStudentsByAccountID(arg1, arg2, arg3) {
+  return window['go']['canvas']['Controller']['GetInactiveStudentsByAccountID'](arg1, arg2, arg3);
+}
+
+export function GetMasteryReportByStudent(arg1, arg2) {
+  return window['go']['canvas']['Controller']['GetMasteryReportByStudent'](arg1, arg2);
+}
+
 export function GetQualifications() {
   return window['go']['canvas']['Controller']['GetQualifications']();
 }
+
+export function GetRosterResults(arg1, arg2, arg3) {
+  return window['go']['canvas']['Controller']['GetRosterResults'](arg1, arg2, arg3);
+}
+
+export function GetRubricReportByCourse(arg1) {
+  return window['go']['canvas']['Controller']['GetRubricReportByCourse'](arg1);
+}
+
+export function GetStudentEngagementByAccountID(arg1, arg2, arg3) {
+  return window['go']['canvas']['Controller']['GetStudentEngagementByAccountID'](arg1, arg2, arg3);
+}
+
+export function PreviewGradeImport(arg1, arg2) {
+  return window['go']['canvas']['Controller']['PreviewGradeImport'](arg1, arg2);
+}
+
+export function PreviewGradingNudges(arg1, arg2, arg3) {
+  return window['go']['canvas']['Controller']['PreviewGradingNudges'](arg1, arg2, arg3);
+}
+
+export function SendGradingNudges(arg1) {
+  return window['go']['canvas']['Controller']['SendGradingNudges'](arg1);
+}
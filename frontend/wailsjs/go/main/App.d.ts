@@ -5,3 +5,31 @@ import {canvas} from '../models';
 export function ExportAssignmentsResults(arg1:Array<canvas.AssignmentResult>,arg2:string):Promise<void>;
 
 export function ExportAssignmentsStatus(arg1:Array<canvas.Assignment>,arg2:canvas.Account):Promise<void>;
+
+export function ExportAssignmentsStatusXLSX(arg1:Array<canvas.Assignment>,arg2:canvas.Account):Promise<string>;
+
+export function ExportFeedback(arg1:Array<canvas.SubmissionFeedback>,arg2:string):Promise<void>;
+
+export function ExportGradeBreakdowns(arg1:Array<canvas.GradeBreakdown>,arg2:number):Promise<void>;
+
+export function ExportGradeChangeAudit(arg1:Array<canvas.GradeChangeRecord>,arg2:string):Promise<void>;
+
+export function ExportGradeImportPlan(arg1:canvas.GradeImportPlan):Promise<void>;
+
+export function ExportGradingNudges(arg1:Array<canvas.GradingNudge>,arg2:number):Promise<Promise<void>>;
+
+export function ExportInactiveStudents(arg1:Array<canvas.InactiveStudent>,arg2:number):Promise<void>;
+
+export function ExportOutcomeMastery(arg1:Array<canvas.OutcomeMastery>,arg2:string):Promise<void>;
+
+export function ExportRosterResults(arg1:canvas.StudentAssessmentsReport,arg2:boolean):Promise<Promise<string>>;
+
+export function ExportRubricReport(arg1:Array<canvas.RubricCriterionResult>,arg2:number):Promise<void>;
+
+export function ExportStudentEngagement(arg1:Array<canvas.StudentEngagement>,arg2:number):Promise<void>;
+
+export function ReadGradeImport(arg1:string):Promise<Array<canvas.GradeImportRow>>;
+
+export function ReadRoster(arg1:string):Promise<Promise<Array<string>>>;
+
+export function SelectCSVFile(arg1:string):Promise<string>;
@@ -9,3 +9,59 @@ export function ExportAssignmentsResults(arg1, arg2) {
 export function ExportAssignmentsStatus(arg1, arg2) {
   return window['go']['main']['App']['ExportAssignmentsStatus'](arg1, arg2);
 }
+
+export function ExportAssignmentsStatusXLSX(arg1, arg2) {
+  return window['go']['main']['App']['ExportAssignmentsStatusXLSX'](arg1, arg2);
+}
+
+export function ExportFeedback(arg1, arg2) {
+  return window['go']['main']['App']['ExportFeedback'](arg1, arg2);
+}
+
+export function ExportGradeBreakdowns(arg1, arg2) {
+  return window['go']['main']['App']['ExportGradeBreakdowns'](arg1, arg2);
+}
+
+export function ExportGradeChangeAudit(arg1, arg2) {
+  return window['go']['main']['App']['ExportGradeChangeAudit'](arg1, arg2);
+}
+
+export function ExportGradeImportPlan(arg1) {
+  return window['go']['main']['App']['ExportGradeImportPlan'](arg1);
+}
+
+export function ExportGradingNudges(arg1, arg2) {
+  return window['go']['main']['App']['ExportGradingNudges'](arg1, arg2);
+}
+
+export function ExportInactiveStudents(arg1, arg2) {
+  return window['go']['main']['App']['ExportInactiveStudents'](arg1, arg2);
+}
+
+export function ExportOutcomeMastery(arg1, arg2) {
+  return window['go']['main']['App']['ExportOutcomeMastery'](arg1, arg2);
+}
+
+export function ExportRosterResults(arg1, arg2) {
+  return window['go']['main']['App']['ExportRosterResults'](arg1, arg2);
+}
+
+export function ExportRubricReport(arg1, arg2) {
+  return window['go']['main']['App']['ExportRubricReport'](arg1, arg2);
+}
+
+export function ExportStudentEngagement(arg1, arg2) {
+  return window['go']['main']['App']['ExportStudentEngagement'](arg1, arg2);
+}
+
+export function ReadGradeImport(arg1) {
+  return window['go']['main']['App']['ReadGradeImport'](arg1);
+}
+
+export function ReadRoster(arg1) {
+  return window['go']['main']['App']['ReadRoster'](arg1);
+}
+
+export function SelectCSVFile(arg1) {
+  return window['go']['main']['App']['SelectCSVFile'](arg1);
+}
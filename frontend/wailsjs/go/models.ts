@@ -7,6 +7,21 @@ export namespace canvas {
 	    OBSERVER = "observer",
 	    DESIGNER = "designer",
 	}
+	export enum CourseState {
+	    CREATED = "created",
+	    CLAIMED = "claimed",
+	    AVAILABLE = "available",
+	    COMPLETED = "completed",
+	    DELETED = "deleted",
+	    ALL = "all",
+	    UNPUBLISHED = "unpublished",
+	}
+	export enum CourseSort {
+	    COURSE_NAME = "course_name",
+	    SIS_COURSE_ID = "sis_course_id",
+	    TEACHER = "teacher",
+	    ACCOUNT_NAME = "account_name",
+	}
 	export enum EnrollmentType {
 	    TEACHER = "TeacherEnrollment",
 	    STUDENT = "StudentEnrollment",
@@ -23,6 +38,26 @@ export namespace canvas {
 	    UPCOMING = "upcoming",
 	    FUTURE = "future",
 	}
+	export enum GradeChangeStatus {
+	    NEW = "new",
+	    CHANGED = "changed",
+	    UNCHANGED = "unchanged",
+	    UNKNOWN_STUDENT = "unknown_student",
+	    UNKNOWN_ASSIGNMENT = "unknown_assignment",
+	    CONFLICT = "conflict",
+	}
+	export enum GradeChangeScope {
+	    ASSIGNMENT = "assignments",
+	    COURSE = "courses",
+	    STUDENT = "students",
+	    GRADER = "graders",
+	}
+	export enum UserIdentifierType {
+	    SIS_USER_ID = "sis_user_id",
+	    LOGIN_ID = "login_id",
+	    EMAIL = "email",
+	    AUTO = "auto",
+	}
 	export class Account {
 	    id: number;
 	    name: string;
@@ -79,11 +114,26 @@ export namespace canvas {
 	        this.needs_grading_count = source["needs_grading_count"];
 	    }
 	}
+	export class SectionTeacher {
+	    id: number;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionTeacher(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	    }
+	}
 	export class Assignment {
 	    id: number;
 	    course_id: number;
 	    qualification: string;
 	    course_name: string;
+	    term: string;
 	    name: string;
 	    due_at: string;
 	    unlock_at: string;
@@ -92,11 +142,16 @@ export namespace canvas {
 	    section: string;
 	    needs_grading_section: number;
 	    teachers: string;
+	    section_teachers: SectionTeacher[];
 	    status: string;
 	    published: boolean;
 	    gradebook_url: string;
 	    needs_grading_count_by_section: SectionNeedsGrading[];
 	    all_dates: AssignmentDate[];
+	    rubric: RubricCriterion[];
+	    quiz_id: number;
+	    is_quiz_assignment: boolean;
+	    is_quiz_lti_assignment: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Assignment(source);
@@ -108,6 +163,7 @@ export namespace canvas {
 	        this.course_id = source["course_id"];
 	        this.qualification = source["qualification"];
 	        this.course_name = source["course_name"];
+	        this.term = source["term"];
 	        this.name = source["name"];
 	        this.due_at = source["due_at"];
 	        this.unlock_at = source["unlock_at"];
@@ -116,11 +172,16 @@ export namespace canvas {
 	        this.section = source["section"];
 	        this.needs_grading_section = source["needs_grading_section"];
 	        this.teachers = source["teachers"];
+	        this.section_teachers = this.convertValues(source["section_teachers"], SectionTeacher);
 	        this.status = source["status"];
 	        this.published = source["published"];
 	        this.gradebook_url = source["gradebook_url"];
 	        this.needs_grading_count_by_section = this.convertValues(source["needs_grading_count_by_section"], SectionNeedsGrading);
 	        this.all_dates = this.convertValues(source["all_dates"], AssignmentDate);
+	        this.rubric = this.convertValues(source["rubric"], RubricCriterion);
+	        this.quiz_id = source["quiz_id"];
+	        this.is_quiz_assignment = source["is_quiz_assignment"];
+	        this.is_quiz_lti_assignment = source["is_quiz_lti_assignment"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -143,6 +204,12 @@ export namespace canvas {
 	}
 	export class AssignmentResult {
 	    assignment_id: number;
+	    course_id: number;
+	    UserSisID: string;
+	    StudentName: string;
+	    Qualification: string;
+	    CourseName: string;
+	    Term: string;
 	    title: string;
 	    max_score: number;
 	    min_score: number;
@@ -150,6 +217,11 @@ export namespace canvas {
 	    submission: any;
 	    "due-at": string;
 	    status: string;
+	    quiz_type: string;
+	    attempts: number;
+	    time_spent: string;
+	    kept_score?: number;
+	    quiz_error: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new AssignmentResult(source);
@@ -158,12 +230,23 @@ export namespace canvas {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.assignment_id = source["assignment_id"];
+	        this.course_id = source["course_id"];
+	        this.UserSisID = source["UserSisID"];
+	        this.StudentName = source["StudentName"];
+	        this.Qualification = source["Qualification"];
+	        this.CourseName = source["CourseName"];
+	        this.Term = source["Term"];
 	        this.title = source["title"];
 	        this.max_score = source["max_score"];
 	        this.min_score = source["min_score"];
 	        this.submission = this.convertValues(source["submission"], Object);
 	        this["due-at"] = source["due-at"];
 	        this.status = source["status"];
+	        this.quiz_type = source["quiz_type"];
+	        this.attempts = source["attempts"];
+	        this.time_spent = source["time_spent"];
+	        this.kept_score = source["kept_score"];
+	        this.quiz_error = source["quiz_error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -196,6 +279,7 @@ export namespace canvas {
 	    end_at: string;
 	    is_public: boolean;
 	    enrollment_term_id: number;
+	    term?: Term;
 	    // Go type: struct { ID int "json:\"id\""; Name string "json:\"name\""; WorkflowState string "json:\"workflow_state\"" }
 	    account: any;
 	
@@ -216,6 +300,7 @@ export namespace canvas {
 	        this.end_at = source["end_at"];
 	        this.is_public = source["is_public"];
 	        this.enrollment_term_id = source["enrollment_term_id"];
+	        this.term = this.convertValues(source["term"], Term);
 	        this.account = this.convertValues(source["account"], Object);
 	    }
 	
@@ -237,12 +322,51 @@ export namespace canvas {
 		    return a;
 		}
 	}
+	export class CourseQueryOptions {
+	    enrollment_types: CourseEnrollmentType[];
+	    states: CourseState[];
+	    published?: boolean;
+	    search_term: string;
+	    starts_before: string;
+	    ends_after: string;
+	    with_enrollments?: boolean;
+	    blueprint?: boolean;
+	    include_teachers: boolean;
+	    sort: CourseSort;
+	    order: string;
+	    by_subaccounts: number[];
+	    enrollment_term_id: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CourseQueryOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enrollment_types = source["enrollment_types"];
+	        this.states = source["states"];
+	        this.published = source["published"];
+	        this.search_term = source["search_term"];
+	        this.starts_before = source["starts_before"];
+	        this.ends_after = source["ends_after"];
+	        this.with_enrollments = source["with_enrollments"];
+	        this.blueprint = source["blueprint"];
+	        this.include_teachers = source["include_teachers"];
+	        this.sort = source["sort"];
+	        this.order = source["order"];
+	        this.by_subaccounts = source["by_subaccounts"];
+	        this.enrollment_term_id = source["enrollment_term_id"];
+	    }
+	}
 	export class Enrollment {
 	    id: number;
 	    user_id: number;
 	    course_id: number;
 	    course_section_id: number;
 	    sis_section_id: string;
+	    enrollment_state: string;
+	    last_activity_at: string;
+	    total_activity_time: number;
 	    // Go type: struct { HtmlUrl string "json:\"html_url\""; CurrentScore float32 "json:\"current_score\""; CurrentGrade string "json:\"current_grade\""; FinalScore float32 "json:\"final_score\""; FinalGrade string "json:\"final_grade\"" }
 	    grades: any;
 	    // Go type: struct { Name string "json:\"name\""; SISUserID string "json:\"sis_user_id\"" }
@@ -259,6 +383,9 @@ export namespace canvas {
 	        this.course_id = source["course_id"];
 	        this.course_section_id = source["course_section_id"];
 	        this.sis_section_id = source["sis_section_id"];
+	        this.enrollment_state = source["enrollment_state"];
+	        this.last_activity_at = source["last_activity_at"];
+	        this.total_activity_time = source["total_activity_time"];
 	        this.grades = this.convertValues(source["grades"], Object);
 	        this.user = this.convertValues(source["user"], Object);
 	    }
@@ -364,6 +491,28 @@ export namespace canvas {
 		    return a;
 		}
 	}
+	export class Term {
+	    id: number;
+	    name: string;
+	    sis_term_id: string;
+	    start_at: string;
+	    end_at: string;
+	    workflow_state: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Term(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.sis_term_id = source["sis_term_id"];
+	        this.start_at = source["start_at"];
+	        this.end_at = source["end_at"];
+	        this.workflow_state = source["workflow_state"];
+	    }
+	}
 	export class User {
 	    id: number;
 	    name: string;
@@ -380,6 +529,894 @@ export namespace canvas {
 	        this.sis_user_id = source["sis_user_id"];
 	    }
 	}
+	export class GradeImportRow {
+	    student_id: string;
+	    assignment: string;
+	    grade: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GradeImportRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.student_id = source["student_id"];
+	        this.assignment = source["assignment"];
+	        this.grade = source["grade"];
+	    }
+	}
+	export class GradeChange {
+	    row: number;
+	    student_id: string;
+	    student_name: string;
+	    user_id: number;
+	    assignment: string;
+	    assignment_id: number;
+	    current_grade: string;
+	    new_grade: string;
+	    status: GradeChangeStatus;
+	    status_details: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GradeChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.user_id = source["user_id"];
+	        this.assignment = source["assignment"];
+	        this.assignment_id = source["assignment_id"];
+	        this.current_grade = source["current_grade"];
+	        this.new_grade = source["new_grade"];
+	        this.status = source["status"];
+	        this.status_details = source["status_details"];
+	    }
+	}
+	export class GradeImportPlan {
+	    course_id: number;
+	    course_name: string;
+	    changes: GradeChange[];
+	    summary: {[key: string]: number};
+	
+	    static createFrom(source: any = {}) {
+	        return new GradeImportPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.course_id = source["course_id"];
+	        this.course_name = source["course_name"];
+	        this.changes = this.convertValues(source["changes"], GradeChange);
+	        this.summary = source["summary"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GradeUpdateResult {
+	    user_id: number;
+	    sis_user_id: string;
+	    posted_grade: string;
+	    grade: string;
+	    score?: number;
+	    excused: boolean;
+	    status: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GradeUpdateResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user_id = source["user_id"];
+	        this.sis_user_id = source["sis_user_id"];
+	        this.posted_grade = source["posted_grade"];
+	        this.grade = source["grade"];
+	        this.score = source["score"];
+	        this.excused = source["excused"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	    }
+	}
+	export class JournalOperation {
+	    id: string;
+	    description: string;
+	    time: string;
+	    entries: number;
+	    rolled_back_by: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JournalOperation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.description = source["description"];
+	        this.time = source["time"];
+	        this.entries = source["entries"];
+	        this.rolled_back_by = source["rolled_back_by"];
+	    }
+	}
+	export class RollbackResult {
+	    course_id: number;
+	    assignment_id: number;
+	    user_id: number;
+	    sis_user_id: string;
+	    journal_grade: string;
+	    current_grade: string;
+	    restored_grade: string;
+	    status: string;
+	    details: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RollbackResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.course_id = source["course_id"];
+	        this.assignment_id = source["assignment_id"];
+	        this.user_id = source["user_id"];
+	        this.sis_user_id = source["sis_user_id"];
+	        this.journal_grade = source["journal_grade"];
+	        this.current_grade = source["current_grade"];
+	        this.restored_grade = source["restored_grade"];
+	        this.status = source["status"];
+	        this.details = source["details"];
+	    }
+	}
+	export class RollbackReport {
+	    operation_id: string;
+	    rollback_operation_id: string;
+	    results: RollbackResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RollbackReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operation_id = source["operation_id"];
+	        this.rollback_operation_id = source["rollback_operation_id"];
+	        this.results = this.convertValues(source["results"], RollbackResult);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SubmissionFeedback {
+	    qualification: string;
+	    course_name: string;
+	    term: string;
+	    student_id: string;
+	    student_name: string;
+	    assignment: string;
+	    grade: string;
+	    graded_at: string;
+	    comment_count: number;
+	    authors: string;
+	    last_comment_at: string;
+	    feedback: string;
+	    attachments: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubmissionFeedback(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.qualification = source["qualification"];
+	        this.course_name = source["course_name"];
+	        this.term = source["term"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.assignment = source["assignment"];
+	        this.grade = source["grade"];
+	        this.graded_at = source["graded_at"];
+	        this.comment_count = source["comment_count"];
+	        this.authors = source["authors"];
+	        this.last_comment_at = source["last_comment_at"];
+	        this.feedback = source["feedback"];
+	        this.attachments = source["attachments"];
+	    }
+	}
+	export class RubricCriterionResult {
+	    course_name: string;
+	    student_id: string;
+	    student_name: string;
+	    assignment: string;
+	    criterion_id: string;
+	    criterion: string;
+	    rating: string;
+	    points: number;
+	    max_points: number;
+	    comments: string;
+	    assessed: boolean;
+	    never_assessed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RubricCriterionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.course_name = source["course_name"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.assignment = source["assignment"];
+	        this.criterion_id = source["criterion_id"];
+	        this.criterion = source["criterion"];
+	        this.rating = source["rating"];
+	        this.points = source["points"];
+	        this.max_points = source["max_points"];
+	        this.comments = source["comments"];
+	        this.assessed = source["assessed"];
+	        this.never_assessed = source["never_assessed"];
+	    }
+	}
+	export class RubricRating {
+	    id: string;
+	    description: string;
+	    long_description: string;
+	    points: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RubricRating(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.description = source["description"];
+	        this.long_description = source["long_description"];
+	        this.points = source["points"];
+	    }
+	}
+	export class RubricCriterion {
+	    id: string;
+	    description: string;
+	    long_description: string;
+	    points: number;
+	    ratings: RubricRating[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RubricCriterion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.description = source["description"];
+	        this.long_description = source["long_description"];
+	        this.points = source["points"];
+	        this.ratings = this.convertValues(source["ratings"], RubricRating);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuizSubmission {
+	    id: number;
+	    quiz_id: number;
+	    user_id: number;
+	    submission_id: number;
+	    attempt: number;
+	    score: number;
+	    kept_score: number;
+	    time_spent: number;
+	    started_at: string;
+	    finished_at: string;
+	    workflow_state: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuizSubmission(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.quiz_id = source["quiz_id"];
+	        this.user_id = source["user_id"];
+	        this.submission_id = source["submission_id"];
+	        this.attempt = source["attempt"];
+	        this.score = source["score"];
+	        this.kept_score = source["kept_score"];
+	        this.time_spent = source["time_spent"];
+	        this.started_at = source["started_at"];
+	        this.finished_at = source["finished_at"];
+	        this.workflow_state = source["workflow_state"];
+	    }
+	}
+	export class GroupAssignment {
+	    id: number;
+	    name: string;
+	    points_possible: number;
+	    published: boolean;
+	    omit_from_final_grade: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupAssignment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.points_possible = source["points_possible"];
+	        this.published = source["published"];
+	        this.omit_from_final_grade = source["omit_from_final_grade"];
+	    }
+	}
+	export class AssignmentGroup {
+	    id: number;
+	    name: string;
+	    position: number;
+	    group_weight: number;
+	    rules: any;
+	    assignments: GroupAssignment[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AssignmentGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.position = source["position"];
+	        this.group_weight = source["group_weight"];
+	        this.rules = source["rules"];
+	        this.assignments = this.convertValues(source["assignments"], GroupAssignment);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GroupSubtotal {
+	    group: string;
+	    weight: number;
+	    points: number;
+	    points_possible: number;
+	    dropped: number;
+	    current_score: number;
+	    final_score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupSubtotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.group = source["group"];
+	        this.weight = source["weight"];
+	        this.points = source["points"];
+	        this.points_possible = source["points_possible"];
+	        this.dropped = source["dropped"];
+	        this.current_score = source["current_score"];
+	        this.final_score = source["final_score"];
+	    }
+	}
+	export class GradeBreakdown {
+	    user_id: number;
+	    student_id: string;
+	    student_name: string;
+	    course_name: string;
+	    weighted: boolean;
+	    groups: GroupSubtotal[];
+	    current_score: number;
+	    final_score: number;
+	    canvas_current_score: number;
+	    canvas_final_score: number;
+	    mismatch: boolean;
+	    mismatch_details: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GradeBreakdown(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user_id = source["user_id"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.course_name = source["course_name"];
+	        this.weighted = source["weighted"];
+	        this.groups = this.convertValues(source["groups"], GroupSubtotal);
+	        this.current_score = source["current_score"];
+	        this.final_score = source["final_score"];
+	        this.canvas_current_score = source["canvas_current_score"];
+	        this.canvas_final_score = source["canvas_final_score"];
+	        this.mismatch = source["mismatch"];
+	        this.mismatch_details = source["mismatch_details"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GradeChangeRecord {
+	    time: string;
+	    event_type: string;
+	    course_name: string;
+	    assignment: string;
+	    student_id: string;
+	    student_name: string;
+	    grader_name: string;
+	    grade_before: string;
+	    grade_after: string;
+	    excused_before: boolean;
+	    excused_after: boolean;
+	    anonymous: boolean;
+	    request_id: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GradeChangeRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.event_type = source["event_type"];
+	        this.course_name = source["course_name"];
+	        this.assignment = source["assignment"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.grader_name = source["grader_name"];
+	        this.grade_before = source["grade_before"];
+	        this.grade_after = source["grade_after"];
+	        this.excused_before = source["excused_before"];
+	        this.excused_after = source["excused_after"];
+	        this.anonymous = source["anonymous"];
+	        this.request_id = source["request_id"];
+	    }
+	}
+	export class OutcomeMastery {
+	    student_id: string;
+	    student_name: string;
+	    qualification: string;
+	    course_name: string;
+	    outcome_group: string;
+	    outcome: string;
+	    mastery_points: number;
+	    score: number;
+	    status: string;
+	    demonstrated_by: string;
+	    demonstrated_at: string;
+	    last_assessed_by: string;
+	    last_assessed_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OutcomeMastery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.qualification = source["qualification"];
+	        this.course_name = source["course_name"];
+	        this.outcome_group = source["outcome_group"];
+	        this.outcome = source["outcome"];
+	        this.mastery_points = source["mastery_points"];
+	        this.score = source["score"];
+	        this.status = source["status"];
+	        this.demonstrated_by = source["demonstrated_by"];
+	        this.demonstrated_at = source["demonstrated_at"];
+	        this.last_assessed_by = source["last_assessed_by"];
+	        this.last_assessed_at = source["last_assessed_at"];
+	    }
+	}
+	export class InactivityCriteria {
+	    inactive_days: number;
+	    min_activity_minutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new InactivityCriteria(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inactive_days = source["inactive_days"];
+	        this.min_activity_minutes = source["min_activity_minutes"];
+	    }
+	}
+	export class InactiveStudent {
+	    qualification: string;
+	    course_name: string;
+	    term: string;
+	    section: string;
+	    teachers: string;
+	    student_id: string;
+	    student_name: string;
+	    last_activity_at: string;
+	    days_inactive: string;
+	    activity_time: string;
+	    current_score: number;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InactiveStudent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.qualification = source["qualification"];
+	        this.course_name = source["course_name"];
+	        this.term = source["term"];
+	        this.section = source["section"];
+	        this.teachers = source["teachers"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.last_activity_at = source["last_activity_at"];
+	        this.days_inactive = source["days_inactive"];
+	        this.activity_time = source["activity_time"];
+	        this.current_score = source["current_score"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class StudentSummary {
+	    id: number;
+	    page_views: number;
+	    max_page_views: number;
+	    page_views_level: string;
+	    participations: number;
+	    max_participations: number;
+	    participations_level: string;
+	    tardiness_breakdown: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudentSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.page_views = source["page_views"];
+	        this.max_page_views = source["max_page_views"];
+	        this.page_views_level = source["page_views_level"];
+	        this.participations = source["participations"];
+	        this.max_participations = source["max_participations"];
+	        this.participations_level = source["participations_level"];
+	        this.tardiness_breakdown = source["tardiness_breakdown"];
+	    }
+	}
+	export class StudentEngagement {
+	    qualification: string;
+	    course_name: string;
+	    term: string;
+	    student_id: string;
+	    student_name: string;
+	    page_views: number;
+	    page_views_level: string;
+	    participations: number;
+	    participations_level: string;
+	    on_time: number;
+	    late: number;
+	    missing: number;
+	    floating: number;
+	    active_days: number;
+	    last_page_view_at: string;
+	    last_participation_at: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudentEngagement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.qualification = source["qualification"];
+	        this.course_name = source["course_name"];
+	        this.term = source["term"];
+	        this.student_id = source["student_id"];
+	        this.student_name = source["student_name"];
+	        this.page_views = source["page_views"];
+	        this.page_views_level = source["page_views_level"];
+	        this.participations = source["participations"];
+	        this.participations_level = source["participations_level"];
+	        this.on_time = source["on_time"];
+	        this.late = source["late"];
+	        this.missing = source["missing"];
+	        this.floating = source["floating"];
+	        this.active_days = source["active_days"];
+	        this.last_page_view_at = source["last_page_view_at"];
+	        this.last_participation_at = source["last_participation_at"];
+	        this.error = source["error"];
+	    }
+	}
+	export class GradingNudge {
+	    teacher_id: number;
+	    teacher_name: string;
+	    sections: number;
+	    needs_grading: number;
+	    subject: string;
+	    body: string;
+	    assignments: Assignment[];
+	    sent: boolean;
+	    conversation_id: number;
+	    error: string;
+	    journal_error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GradingNudge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.teacher_id = source["teacher_id"];
+	        this.teacher_name = source["teacher_name"];
+	        this.sections = source["sections"];
+	        this.needs_grading = source["needs_grading"];
+	        this.subject = source["subject"];
+	        this.body = source["body"];
+	        this.assignments = this.convertValues(source["assignments"], Assignment);
+	        this.sent = source["sent"];
+	        this.conversation_id = source["conversation_id"];
+	        this.error = source["error"];
+	        this.journal_error = source["journal_error"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GradingNudgePlan {
+	    nudges: GradingNudge[];
+	    unassigned: Assignment[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GradingNudgePlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.nudges = this.convertValues(source["nudges"], GradingNudge);
+	        this.unassigned = this.convertValues(source["unassigned"], Assignment);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StudentAssessments {
+	    identifier: string;
+	    user: User;
+	    enrollments: EnrollmentResult[];
+	    assignments: AssignmentResult[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudentAssessments(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.identifier = source["identifier"];
+	        this.user = this.convertValues(source["user"], User);
+	        this.enrollments = this.convertValues(source["enrollments"], EnrollmentResult);
+	        this.assignments = this.convertValues(source["assignments"], AssignmentResult);
+	        this.error = source["error"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StudentAssessmentsReport {
+	    students: StudentAssessments[];
+	    not_found: UnresolvedUser[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StudentAssessmentsReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.students = this.convertValues(source["students"], StudentAssessments);
+	        this.not_found = this.convertValues(source["not_found"], UnresolvedUser);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskProgress {
+	    task: string;
+	    done: number;
+	    total: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task = source["task"];
+	        this.done = source["done"];
+	        this.total = source["total"];
+	        this.message = source["message"];
+	    }
+	}
+	export class EnrollmentResult {
+	    CourseID: number;
+	    StudentID: string;
+	    StudentName: string;
+	    Qualification: string;
+	    CourseName: string;
+	    Term: string;
+	    CourseStatus: string;
+	    CurrentGrade: string;
+	    CurrentScore: number;
+	    GradesURL: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnrollmentResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.CourseID = source["CourseID"];
+	        this.StudentID = source["StudentID"];
+	        this.StudentName = source["StudentName"];
+	        this.Qualification = source["Qualification"];
+	        this.CourseName = source["CourseName"];
+	        this.Term = source["Term"];
+	        this.CourseStatus = source["CourseStatus"];
+	        this.CurrentGrade = source["CurrentGrade"];
+	        this.CurrentScore = source["CurrentScore"];
+	        this.GradesURL = source["GradesURL"];
+	    }
+	}
+	export class UnresolvedUser {
+	    identifier: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UnresolvedUser(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.identifier = source["identifier"];
+	        this.reason = source["reason"];
+	    }
+	}
 
 }
 
@@ -36,7 +36,17 @@ func main() {
 
 	rl := rate.NewLimiter(rate.Every(10*time.Second), 100) // 100 requests every 10 seconds
 	client := canvas.NewAPIClient(baseURL, accessToken, pageSize, http.DefaultClient, rl)
+	client.UseGraphQL, err = strconv.ParseBool(getenv("CANVAS_USE_GRAPHQL", "false"))
+	if err != nil {
+		println("Error:", err.Error())
+	}
+	client.Journal = canvas.NewJournal(getenv("CANVAS_JOURNAL_PATH", canvas.DefaultJournalPath()))
+	client.Concurrency, err = strconv.Atoi(getenv("CANVAS_CONCURRENCY", "4"))
+	if err != nil {
+		println("Error:", err.Error())
+	}
 	controller := canvas.NewController(client)
+	controller.OnProgress = app.emitProgress
 
 	// Create application with options
 	err = wails.Run(&options.App{
@@ -58,6 +68,12 @@ func main() {
 			canvas.AllAssignmentBucket,
 			canvas.AllCourseEnrollmentType,
 			canvas.AllEnrollmentType,
+			canvas.AllCourseState,
+			canvas.AllCourseSort,
+			canvas.AllUserIdentifierType,
+			canvas.AllLatePolicyStatus,
+			canvas.AllGradeChangeStatus,
+			canvas.AllGradeChangeScope,
 		},
 	})
 
@@ -0,0 +1,154 @@
+package mirror
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ninja-software/terror/v2"
+)
+
+// Mirror is a local SQLite copy of the accounts, courses, sections, enrollments, assignments and
+// submissions the reports read, so they can be built in seconds and without Canvas. Rows keep the
+// Canvas JSON in data, the other columns are only there to look rows up.
+type Mirror struct {
+	Path string
+	db   *sql.DB
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS settings (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS accounts (
+	id        INTEGER PRIMARY KEY,
+	name      TEXT NOT NULL,
+	data      TEXT NOT NULL,
+	synced_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS courses (
+	id                    INTEGER PRIMARY KEY,
+	account_id            INTEGER NOT NULL,
+	workflow_state        TEXT NOT NULL,
+	data                  TEXT NOT NULL,
+	synced_at             TEXT NOT NULL DEFAULT '',
+	submissions_synced_at TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS courses_account_id ON courses (account_id);
+CREATE TABLE IF NOT EXISTS sections (
+	id        INTEGER PRIMARY KEY,
+	course_id INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sections_course_id ON sections (course_id);
+CREATE TABLE IF NOT EXISTS enrollments (
+	id        INTEGER PRIMARY KEY,
+	course_id INTEGER NOT NULL,
+	user_id   INTEGER NOT NULL,
+	type      TEXT NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS enrollments_course_id ON enrollments (course_id);
+CREATE INDEX IF NOT EXISTS enrollments_user_id ON enrollments (user_id);
+CREATE TABLE IF NOT EXISTS assignments (
+	id        INTEGER PRIMARY KEY,
+	course_id INTEGER NOT NULL,
+	data      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS assignments_course_id ON assignments (course_id);
+CREATE TABLE IF NOT EXISTS submissions (
+	id             INTEGER PRIMARY KEY,
+	course_id      INTEGER NOT NULL,
+	assignment_id  INTEGER NOT NULL,
+	user_id        INTEGER NOT NULL,
+	workflow_state TEXT NOT NULL,
+	data           TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS submissions_course_id ON submissions (course_id);
+`
+
+// DefaultPath is mirror.db in the user config folder.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "canvas-mirror.db"
+	}
+
+	return filepath.Join(dir, "canvas-desktop", "mirror.db")
+}
+
+// Open creates the mirror file and its tables when they don't exist yet.
+func Open(path string) (*Mirror, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, terror.Error(err, "cannot create mirror folder")
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000", path))
+	if err != nil {
+		return nil, terror.Error(err, fmt.Sprintf("cannot open mirror: %s", path))
+	}
+	// Syncs write from several goroutines, one connection keeps SQLite from returning busy
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, terror.Error(err, fmt.Sprintf("cannot create mirror tables: %s", path))
+	}
+
+	return &Mirror{Path: path, db: db}, nil
+}
+
+func (m *Mirror) Close() error {
+	return m.db.Close()
+}
+
+func (m *Mirror) setting(key string) (string, error) {
+	value := ""
+	err := m.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", terror.Error(err, fmt.Sprintf("cannot read mirror setting %s", key))
+	}
+
+	return value, nil
+}
+
+func (m *Mirror) setSetting(key string, value string) error {
+	_, err := m.db.Exec(`INSERT INTO settings (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	if err != nil {
+		return terror.Error(err, fmt.Sprintf("cannot save mirror setting %s", key))
+	}
+
+	return nil
+}
+
+// scanData unmarshals the data column of each row into a new T.
+func scanData[T any](rows *sql.Rows) ([]*T, error) {
+	defer rows.Close()
+
+	values := []*T{}
+	for rows.Next() {
+		data := ""
+		if err := rows.Scan(&data); err != nil {
+			return nil, terror.Error(err, "cannot read mirror row")
+		}
+
+		value := new(T)
+		if err := json.Unmarshal([]byte(data), value); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal mirror row")
+		}
+		values = append(values, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, terror.Error(err, "cannot read mirror rows")
+	}
+
+	return values, nil
+}
@@ -0,0 +1,243 @@
+package mirror
+
+import (
+	"canvas-desktop/canvas"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// The report methods match the APIClient methods of the same name, so a report can read either.
+
+func (m *Mirror) GetAccountByID(accountID int) (*canvas.Account, error) {
+	data := ""
+	err := m.db.QueryRow(`SELECT data FROM accounts WHERE id = ?`, accountID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, terror.Error(fmt.Errorf("account %d is not mirrored, run mirror-sync first", accountID), "cannot read mirrored account")
+	}
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirrored account")
+	}
+
+	account := &canvas.Account{}
+	if err := json.Unmarshal([]byte(data), account); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal mirrored account")
+	}
+
+	return account, nil
+}
+
+// GetCoursesByAccount filters the mirrored courses by the states, term and search of opts, the other
+// options were applied when the account was synced.
+func (m *Mirror) GetCoursesByAccount(account *canvas.Account, opts *canvas.CourseQueryOptions) ([]*canvas.Course, error) {
+	rows, err := m.db.Query(`SELECT data FROM courses WHERE account_id = ? ORDER BY id`, account.ID)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirrored courses")
+	}
+
+	courses, err := scanData[canvas.Course](rows)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		return courses, nil
+	}
+
+	states := make(map[string]bool)
+	for _, state := range opts.States {
+		switch state {
+		case canvas.UnpublishedCourse:
+			states[string(canvas.CreatedCourse)] = true
+			states[string(canvas.ClaimedCourse)] = true
+		default:
+			states[string(state)] = true
+		}
+	}
+
+	filtered := []*canvas.Course{}
+	for _, course := range courses {
+		if len(states) > 0 && !states[string(canvas.AllCourse)] && !states[course.WorkflowState] {
+			continue
+		}
+		if opts.EnrollmentTermID != 0 && course.EnrollmentTermID != opts.EnrollmentTermID {
+			continue
+		}
+		search := strings.ToLower(opts.SearchTerm)
+		if search != "" && !strings.Contains(strings.ToLower(course.Name), search) && !strings.Contains(strings.ToLower(course.CourseCode), search) {
+			continue
+		}
+		filtered = append(filtered, course)
+	}
+
+	return filtered, nil
+}
+
+// GetAssignmentsByAccount only knows the ungraded bucket, the others depend on the user asking.
+// Needs grading counts are as of the last sync.
+func (m *Mirror) GetAssignmentsByAccount(account *canvas.Account, bucket canvas.AssignmentBucket, opts *canvas.CourseQueryOptions) ([]*canvas.Assignment, error) {
+	if bucket != canvas.UngradedBucket {
+		return nil, terror.Error(fmt.Errorf("bucket %s", bucket), "only the ungraded bucket can be read from the mirror")
+	}
+	if opts == nil {
+		opts = canvas.DefaultCourseQueryOptions()
+	}
+
+	baseURL, err := m.setting("base_url")
+	if err != nil {
+		return nil, err
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/api/v1")
+
+	courses, err := m.GetCoursesByAccount(account, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := []*canvas.Assignment{}
+	for _, course := range courses {
+		sections, err := m.sections(course.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		teachers, err := m.enrollments(course.ID, canvas.TeacherEnrollment)
+		if err != nil {
+			return nil, err
+		}
+		directory := canvas.SectionDirectory(sections, teachers)
+
+		_assignments, err := m.assignments(course.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, assignment := range _assignments {
+			if assignment.NeedsGradingCount == 0 {
+				continue
+			}
+
+			for _, section := range assignment.NeedsGradingCountBySection {
+				if directory[section.SectionID] == nil {
+					continue
+				}
+				assignments = append(assignments, canvas.NewSectionAssignment(course, assignment, section, directory[section.SectionID], bucket, baseURL))
+			}
+		}
+	}
+
+	return assignments, nil
+}
+
+func (m *Mirror) GetUngradedSubmissionsByAccount(account *canvas.Account, opts *canvas.CourseQueryOptions) ([]*canvas.Submission, error) {
+	if opts == nil {
+		opts = canvas.DefaultCourseQueryOptions()
+	}
+
+	courses, err := m.GetCoursesByAccount(account, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := []*canvas.Submission{}
+	for _, course := range courses {
+		assignments, err := m.assignments(course.ID)
+		if err != nil {
+			return nil, err
+		}
+		assignmentsByID := make(map[int]*canvas.Assignment)
+		for _, assignment := range assignments {
+			assignmentsByID[assignment.ID] = assignment
+		}
+
+		rows, err := m.db.Query(`SELECT data FROM submissions WHERE course_id = ? AND workflow_state IN (?, ?, ?) ORDER BY id`,
+			course.ID, canvas.SubmittedSubmission, canvas.PendingReviewSubmission, canvas.GradedSubmission)
+		if err != nil {
+			return nil, terror.Error(err, "cannot read mirrored submissions")
+		}
+		_submissions, err := scanData[canvas.Submission](rows)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, submission := range _submissions {
+			// Submissions of deleted assignments stay until the next full sync
+			assignment := assignmentsByID[submission.AssignmentID]
+			if assignment == nil || !submission.NeedsGrading() {
+				continue
+			}
+
+			submission.Assignment.Name = assignment.Name
+			submission.Assignment.DueAt = assignment.DueAt
+			submission.FillReportFields(course)
+			submission.Account = account.Name
+			submissions = append(submissions, submission)
+		}
+	}
+
+	return submissions, nil
+}
+
+func (m *Mirror) sections(courseID int) ([]*canvas.Section, error) {
+	rows, err := m.db.Query(`SELECT data FROM sections WHERE course_id = ? ORDER BY id`, courseID)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirrored sections")
+	}
+
+	return scanData[canvas.Section](rows)
+}
+
+func (m *Mirror) enrollments(courseID int, enrollmentType canvas.EnrollmentType) ([]*canvas.Enrollment, error) {
+	rows, err := m.db.Query(`SELECT data FROM enrollments WHERE course_id = ? AND type = ? ORDER BY id`, courseID, enrollmentType)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirrored enrollments")
+	}
+
+	return scanData[canvas.Enrollment](rows)
+}
+
+func (m *Mirror) assignments(courseID int) ([]*canvas.Assignment, error) {
+	rows, err := m.db.Query(`SELECT data FROM assignments WHERE course_id = ? ORDER BY id`, courseID)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirrored assignments")
+	}
+
+	return scanData[canvas.Assignment](rows)
+}
+
+type AccountStatus struct {
+	AccountID   int    `json:"account_id"`
+	Account     string `json:"account"`
+	SyncedAt    string `json:"synced_at"`
+	Courses     int    `json:"courses"`
+	Assignments int    `json:"assignments"`
+	Submissions int    `json:"submissions"`
+}
+
+// Status lists the mirrored accounts with their row counts.
+func (m *Mirror) Status() ([]*AccountStatus, error) {
+	rows, err := m.db.Query(`
+		SELECT a.id, a.name, a.synced_at,
+			(SELECT COUNT(*) FROM courses c WHERE c.account_id = a.id),
+			(SELECT COUNT(*) FROM assignments s JOIN courses c ON c.id = s.course_id WHERE c.account_id = a.id),
+			(SELECT COUNT(*) FROM submissions s JOIN courses c ON c.id = s.course_id WHERE c.account_id = a.id)
+		FROM accounts a ORDER BY a.name`)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirror status")
+	}
+	defer rows.Close()
+
+	statuses := []*AccountStatus{}
+	for rows.Next() {
+		status := &AccountStatus{}
+		err := rows.Scan(&status.AccountID, &status.Account, &status.SyncedAt, &status.Courses, &status.Assignments, &status.Submissions)
+		if err != nil {
+			return nil, terror.Error(err, "cannot read mirror status")
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, rows.Err()
+}
@@ -0,0 +1,302 @@
+package mirror
+
+import (
+	"canvas-desktop/canvas"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+)
+
+// Submissions are fetched again from a little before the last sync, in case the clocks of Canvas and
+// this machine differ.
+const syncOverlap = 10 * time.Minute
+
+type SyncResult struct {
+	AccountID   int    `json:"account_id"`
+	Account     string `json:"account"`
+	Courses     int    `json:"courses"`
+	Skipped     int    `json:"skipped"`
+	Removed     int    `json:"removed"`
+	Assignments int    `json:"assignments"`
+	Submissions int    `json:"submissions"`
+	Started     string `json:"started"`
+	Finished    string `json:"finished"`
+}
+
+// Sync copies the courses of an account with student enrollments and everything in them into the mirror.
+// Sections, enrollments and assignments are replaced on every sync as Canvas can't list what changed in
+// them. After the first sync only submissions submitted or graded since are fetched, and concluded courses
+// are skipped, unless full is set.
+func (m *Mirror) Sync(client *canvas.APIClient, accountID int, full bool) (*SyncResult, error) {
+	result := &SyncResult{AccountID: accountID, Started: time.Now().UTC().Format(time.RFC3339)}
+
+	// A mirror holds one Canvas instance, IDs of two would mix
+	baseURL, err := m.setting("base_url")
+	if err != nil {
+		return nil, err
+	}
+	if baseURL != "" && baseURL != client.BaseURL {
+		return nil, terror.Error(fmt.Errorf("mirror of %s", baseURL), fmt.Sprintf("cannot sync %s into this mirror, use another mirror file", client.BaseURL))
+	}
+	if err := m.setSetting("base_url", client.BaseURL); err != nil {
+		return nil, err
+	}
+
+	account, err := client.GetAccountByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	result.Account = account.Name
+
+	courses, err := client.GetCoursesByAccount(account, canvas.DefaultCourseQueryOptions())
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving courses")
+	}
+
+	synced, err := m.courseSyncTimes(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	toSync := []*canvas.Course{}
+	for _, course := range courses {
+		state := synced[course.ID]
+		if !full && state != nil && state.syncedAt != "" && course.WorkflowState == string(canvas.CompletedCourse) && state.workflowState == course.WorkflowState {
+			result.Skipped++
+			continue
+		}
+		toSync = append(toSync, course)
+	}
+
+	var mu sync.Mutex
+	err = canvas.ForEach(len(toSync), client.Concurrency, func(i int) error {
+		course := toSync[i]
+		since := ""
+		if state := synced[course.ID]; !full && state != nil && state.submissionsSyncedAt != "" {
+			last, err := time.Parse(time.RFC3339, state.submissionsSyncedAt)
+			if err == nil {
+				since = last.Add(-syncOverlap).Format(time.RFC3339)
+			}
+		}
+
+		data, err := fetchCourse(client, course, since)
+		if err != nil {
+			return terror.Error(err, fmt.Sprintf("cannot sync %s", course.Name))
+		}
+
+		err = m.saveCourse(accountID, data, since == "")
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		result.Courses++
+		result.Assignments += len(data.assignments)
+		result.Submissions += len(data.submissions)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[int]bool)
+	for _, course := range courses {
+		current[course.ID] = true
+	}
+	for courseID := range synced {
+		if !current[courseID] {
+			if err := m.removeCourse(courseID); err != nil {
+				return nil, err
+			}
+			result.Removed++
+		}
+	}
+
+	result.Finished = time.Now().UTC().Format(time.RFC3339)
+	content, err := json.Marshal(account)
+	if err != nil {
+		return nil, terror.Error(err, "cannot marshal account")
+	}
+	_, err = m.db.Exec(`INSERT INTO accounts (id, name, data, synced_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, data = excluded.data, synced_at = excluded.synced_at`,
+		account.ID, account.Name, string(content), result.Finished)
+	if err != nil {
+		return nil, terror.Error(err, "cannot save account to mirror")
+	}
+
+	return result, nil
+}
+
+type courseSyncState struct {
+	workflowState       string
+	syncedAt            string
+	submissionsSyncedAt string
+}
+
+func (m *Mirror) courseSyncTimes(accountID int) (map[int]*courseSyncState, error) {
+	rows, err := m.db.Query(`SELECT id, workflow_state, synced_at, submissions_synced_at FROM courses WHERE account_id = ?`, accountID)
+	if err != nil {
+		return nil, terror.Error(err, "cannot read mirrored courses")
+	}
+	defer rows.Close()
+
+	states := make(map[int]*courseSyncState)
+	for rows.Next() {
+		id := 0
+		state := &courseSyncState{}
+		if err := rows.Scan(&id, &state.workflowState, &state.syncedAt, &state.submissionsSyncedAt); err != nil {
+			return nil, terror.Error(err, "cannot read mirrored course")
+		}
+		states[id] = state
+	}
+
+	return states, rows.Err()
+}
+
+type courseData struct {
+	course      *canvas.Course
+	sections    []*canvas.Section
+	enrollments []*canvas.Enrollment
+	assignments []*canvas.Assignment
+	submissions []*canvas.Submission
+	// When the submissions were requested, the next sync fetches the ones submitted or graded after it
+	fetchedAt string
+}
+
+func fetchCourse(client *canvas.APIClient, course *canvas.Course, since string) (*courseData, error) {
+	var err error
+	data := &courseData{
+		course:    course,
+		fetchedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	data.sections, err = client.GetSectionsByCourseID(course.ID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving sections")
+	}
+
+	data.enrollments, err = client.GetEnrollmentsByCourseID(course.ID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving enrollments")
+	}
+
+	data.assignments, err = client.GetAssignmentsWithSectionsByCourseID(course.ID)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving assignments")
+	}
+
+	data.submissions, err = client.GetSubmissionsByCourseIDSince(course.ID, since)
+	if err != nil {
+		return nil, terror.Error(err, "error retrieving submissions")
+	}
+
+	return data, nil
+}
+
+// saveCourse replaces the course in one transaction, so an interrupted sync leaves the previous copy.
+// Submissions are added to the ones already mirrored unless all of them were fetched.
+func (m *Mirror) saveCourse(accountID int, data *courseData, allSubmissions bool) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return terror.Error(err, "cannot start mirror transaction")
+	}
+	defer tx.Rollback()
+
+	course := data.course
+	for _, table := range []string{"sections", "enrollments", "assignments"} {
+		if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE course_id = ?`, table), course.ID); err != nil {
+			return terror.Error(err, fmt.Sprintf("cannot clear mirrored %s", table))
+		}
+	}
+	if allSubmissions {
+		if _, err := tx.Exec(`DELETE FROM submissions WHERE course_id = ?`, course.ID); err != nil {
+			return terror.Error(err, "cannot clear mirrored submissions")
+		}
+	}
+
+	err = insert(tx, `INSERT INTO courses (id, account_id, workflow_state, synced_at, submissions_synced_at, data) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET account_id = excluded.account_id, workflow_state = excluded.workflow_state, data = excluded.data,
+		synced_at = excluded.synced_at, submissions_synced_at = excluded.submissions_synced_at`,
+		course, course.ID, accountID, course.WorkflowState, time.Now().UTC().Format(time.RFC3339), data.fetchedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, section := range data.sections {
+		err = insert(tx, `INSERT OR REPLACE INTO sections (id, course_id, data) VALUES (?, ?, ?)`, section, section.ID, course.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, enrollment := range data.enrollments {
+		err = insert(tx, `INSERT OR REPLACE INTO enrollments (id, course_id, user_id, type, data) VALUES (?, ?, ?, ?, ?)`,
+			enrollment, enrollment.ID, course.ID, enrollment.UserID, string(enrollment.Type))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, assignment := range data.assignments {
+		err = insert(tx, `INSERT OR REPLACE INTO assignments (id, course_id, data) VALUES (?, ?, ?)`, assignment, assignment.ID, course.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, submission := range data.submissions {
+		err = insert(tx, `INSERT OR REPLACE INTO submissions (id, course_id, assignment_id, user_id, workflow_state, data) VALUES (?, ?, ?, ?, ?, ?)`,
+			submission, submission.ID, course.ID, submission.AssignmentID, submission.UserID, submission.WorkflowState)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return terror.Error(err, "cannot commit mirror transaction")
+	}
+
+	return nil
+}
+
+// insert runs query with the columns and then value marshalled as the last, data column.
+func insert(tx *sql.Tx, query string, value interface{}, columns ...interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return terror.Error(err, "cannot marshal mirror row")
+	}
+
+	if _, err := tx.Exec(query, append(columns, string(content))...); err != nil {
+		return terror.Error(err, "cannot save mirror row")
+	}
+
+	return nil
+}
+
+func (m *Mirror) removeCourse(courseID int) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return terror.Error(err, "cannot start mirror transaction")
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"sections", "enrollments", "assignments", "submissions"} {
+		if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE course_id = ?`, table), courseID); err != nil {
+			return terror.Error(err, fmt.Sprintf("cannot remove mirrored %s", table))
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM courses WHERE id = ?`, courseID); err != nil {
+		return terror.Error(err, "cannot remove mirrored course")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return terror.Error(err, "cannot commit mirror transaction")
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package mirror
+
+import (
+	"canvas-desktop/canvas"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// fakeCanvas serves one account with the courses, assignments and submissions a sync fetches.
+type fakeCanvas struct {
+	mu          sync.Mutex
+	courses     []*canvas.Course
+	assignments map[int][]*canvas.Assignment
+	submissions map[int][]*canvas.Submission
+	// The since parameter of each submissions request by course, empty for all submissions
+	requests map[int][]string
+}
+
+func newFakeCanvas() *fakeCanvas {
+	long := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	return &fakeCanvas{
+		courses: []*canvas.Course{
+			{ID: 1, Name: "Brakes", WorkflowState: string(canvas.AvailableCourse)},
+			{ID: 2, Name: "Engines", WorkflowState: string(canvas.CompletedCourse)},
+		},
+		assignments: map[int][]*canvas.Assignment{
+			1: {{ID: 10, Name: "Essay"}},
+			2: {{ID: 20, Name: "Report"}},
+		},
+		submissions: map[int][]*canvas.Submission{
+			1: {
+				{ID: 100, UserID: 1, AssignmentID: 10, WorkflowState: string(canvas.SubmittedSubmission), SubmittedAt: long},
+				{ID: 101, UserID: 2, AssignmentID: 10, WorkflowState: string(canvas.GradedSubmission), SubmittedAt: long, GradedAt: long, GradeMatchesCurrentSubmission: true},
+				// Its assignment was deleted
+				{ID: 102, UserID: 3, AssignmentID: 11, WorkflowState: string(canvas.SubmittedSubmission), SubmittedAt: long},
+			},
+			2: {
+				{ID: 200, UserID: 1, AssignmentID: 20, WorkflowState: string(canvas.SubmittedSubmission), SubmittedAt: long},
+			},
+		},
+		requests: map[int][]string{},
+	}
+}
+
+func (f *fakeCanvas) course(id int) *canvas.Course {
+	for _, course := range f.courses {
+		if course.ID == id {
+			return course
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeCanvas) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var value interface{}
+	courseID := 0
+	resource := ""
+	if parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/"); len(parts) > 2 && parts[0] == "courses" {
+		courseID, _ = strconv.Atoi(parts[1])
+		resource = parts[2]
+	}
+	switch {
+	case r.URL.Path == "/accounts/1":
+		value = &canvas.Account{ID: 1, Name: "Automotive"}
+	case r.URL.Path == "/accounts/1/courses":
+		value = f.courses
+	case f.course(courseID) == nil:
+		http.NotFound(w, r)
+		return
+	case resource == "sections":
+		value = []*canvas.Section{}
+	case resource == "enrollments":
+		value = []*canvas.Enrollment{}
+	case resource == "assignments":
+		value = f.assignments[courseID]
+	case resource == "students":
+		query := r.URL.Query()
+		since := ""
+		for _, param := range []string{"submitted_since", "graded_since"} {
+			if query.Get(param) != "" {
+				since = param + "=" + query.Get(param)
+			}
+		}
+		f.requests[courseID] = append(f.requests[courseID], since)
+
+		submissions := []*canvas.Submission{}
+		for _, submission := range f.submissions[courseID] {
+			if since := query.Get("submitted_since"); since != "" && submission.SubmittedAt < since {
+				continue
+			}
+			if since := query.Get("graded_since"); since != "" && submission.GradedAt < since {
+				continue
+			}
+			submissions = append(submissions, submission)
+		}
+		value = submissions
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewEncoder(w).Encode(value)
+}
+
+func openTestMirror(t *testing.T) (*Mirror, *fakeCanvas, *canvas.APIClient) {
+	m, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	fake := newFakeCanvas()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return m, fake, canvas.NewAPIClient(server.URL, "token", 10, server.Client(), rate.NewLimiter(rate.Inf, 1))
+}
+
+func mirroredSubmissionIDs(t *testing.T, m *Mirror, courseID int) []int {
+	rows, err := m.db.Query(`SELECT id FROM submissions WHERE course_id = ? ORDER BY id`, courseID)
+	if err != nil {
+		t.Fatalf("cannot read mirrored submissions: %v", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		id := 0
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestSyncSince(t *testing.T) {
+	m, fake, client := openTestMirror(t)
+
+	if _, err := m.Sync(client, 1, false); err != nil {
+		t.Fatalf("first Sync() error: %v", err)
+	}
+	if got := fake.requests[1]; len(got) != 1 || got[0] != "" {
+		t.Fatalf("first Sync() requested submissions %q, want all of them", got)
+	}
+
+	syncedAt := ""
+	m.db.QueryRow(`SELECT submissions_synced_at FROM courses WHERE id = 1`).Scan(&syncedAt)
+	last, err := time.Parse(time.RFC3339, syncedAt)
+	if err != nil {
+		t.Fatalf("submissions_synced_at %q: %v", syncedAt, err)
+	}
+	since := last.Add(-syncOverlap).Format(time.RFC3339)
+
+	// Submitted just before the first sync finished, inside the overlap
+	fake.submissions[1] = append(fake.submissions[1], &canvas.Submission{
+		ID: 103, UserID: 4, AssignmentID: 10, WorkflowState: string(canvas.SubmittedSubmission), SubmittedAt: last.Add(-time.Minute).Format(time.RFC3339),
+	})
+	fake.requests = map[int][]string{}
+
+	result, err := m.Sync(client, 1, false)
+	if err != nil {
+		t.Fatalf("second Sync() error: %v", err)
+	}
+
+	want := []string{"submitted_since=" + since, "graded_since=" + since}
+	if !reflect.DeepEqual(fake.requests[1], want) {
+		t.Errorf("second Sync() requested submissions %q, want %q", fake.requests[1], want)
+	}
+	if result.Submissions != 1 {
+		t.Errorf("second Sync() fetched %d submissions, want 1", result.Submissions)
+	}
+	if got := mirroredSubmissionIDs(t, m, 1); !reflect.DeepEqual(got, []int{100, 101, 102, 103}) {
+		t.Errorf("mirrored submissions %v, want the earlier ones and 103", got)
+	}
+}
+
+func TestSyncSkipsCompletedCourses(t *testing.T) {
+	tests := []struct {
+		name        string
+		full        bool
+		wantCourses int
+		wantSkipped int
+	}{
+		{name: "incremental", wantCourses: 1, wantSkipped: 1},
+		{name: "full", full: true, wantCourses: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, fake, client := openTestMirror(t)
+			if _, err := m.Sync(client, 1, false); err != nil {
+				t.Fatalf("first Sync() error: %v", err)
+			}
+			fake.requests = map[int][]string{}
+
+			result, err := m.Sync(client, 1, test.full)
+			if err != nil {
+				t.Fatalf("second Sync() error: %v", err)
+			}
+			if result.Courses != test.wantCourses || result.Skipped != test.wantSkipped {
+				t.Errorf("second Sync() synced %d and skipped %d courses, want %d and %d", result.Courses, result.Skipped, test.wantCourses, test.wantSkipped)
+			}
+			if requested := len(fake.requests[2]) > 0; requested != test.full {
+				t.Errorf("second Sync() requested the completed course: %t, want %t", requested, test.full)
+			}
+			if got := mirroredSubmissionIDs(t, m, 2); !reflect.DeepEqual(got, []int{200}) {
+				t.Errorf("mirrored submissions of the completed course %v, want [200]", got)
+			}
+		})
+	}
+}
+
+func TestSyncRemovesCourses(t *testing.T) {
+	m, fake, client := openTestMirror(t)
+	if _, err := m.Sync(client, 1, false); err != nil {
+		t.Fatalf("first Sync() error: %v", err)
+	}
+
+	fake.courses = fake.courses[:1]
+	result, err := m.Sync(client, 1, false)
+	if err != nil {
+		t.Fatalf("second Sync() error: %v", err)
+	}
+	if result.Removed != 1 {
+		t.Errorf("second Sync() removed %d courses, want 1", result.Removed)
+	}
+
+	courses, err := m.GetCoursesByAccount(&canvas.Account{ID: 1}, nil)
+	if err != nil {
+		t.Fatalf("GetCoursesByAccount() error: %v", err)
+	}
+	if len(courses) != 1 || courses[0].ID != 1 {
+		t.Errorf("GetCoursesByAccount() %d courses, want only course 1", len(courses))
+	}
+	if got := mirroredSubmissionIDs(t, m, 2); len(got) != 0 {
+		t.Errorf("submissions %v of the removed course are still mirrored", got)
+	}
+}
+
+func TestGetUngradedSubmissionsByAccount(t *testing.T) {
+	m, _, client := openTestMirror(t)
+	if _, err := m.Sync(client, 1, false); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	account, err := m.GetAccountByID(1)
+	if err != nil {
+		t.Fatalf("GetAccountByID() error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		states []canvas.CourseState
+		want   []int
+	}{
+		{name: "every course", want: []int{100, 200}},
+		{name: "available courses", states: []canvas.CourseState{canvas.AvailableCourse}, want: []int{100}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := canvas.DefaultCourseQueryOptions()
+			opts.States = test.states
+
+			submissions, err := m.GetUngradedSubmissionsByAccount(account, opts)
+			if err != nil {
+				t.Fatalf("GetUngradedSubmissionsByAccount() error: %v", err)
+			}
+
+			got := []int{}
+			for _, submission := range submissions {
+				got = append(got, submission.ID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("GetUngradedSubmissionsByAccount() %v, want %v", got, test.want)
+			}
+
+			submission := submissions[0]
+			if submission.Account != "Automotive" || submission.CourseName != "Brakes" || submission.AssignmentName != "Essay" {
+				t.Errorf("GetUngradedSubmissionsByAccount() report fields %q, %q, %q, want Automotive, Brakes, Essay", submission.Account, submission.CourseName, submission.AssignmentName)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ninja-software/terror/v2"
+	"github.com/robfig/cron/v3"
+)
+
+// Config is read from a JSON file:
+//
+//	{
+//	  "output_dir": "reports",
+//	  "reports": [
+//	    {"name": "ungraded-morning", "cron": "0 6 * * 1-5", "report": "ungraded-assignments", "email_profile": "monday"}
+//	  ]
+//	}
+type Config struct {
+	OutputDir string        `json:"output_dir"`
+	Reports   []*Definition `json:"reports"`
+}
+
+// Definition is one scheduled report. Cron has five fields, minute first, in the local time zone.
+// Accounts of none runs the report for every qualification. States, Term and Search filter the courses
+// like the -state, -term and -search flags, only available courses without States.
+type Definition struct {
+	Name         string   `json:"name"`
+	Cron         string   `json:"cron"`
+	Report       string   `json:"report"`
+	Accounts     []int    `json:"accounts"`
+	States       []string `json:"states"`
+	Term         int      `json:"term"`
+	Search       string   `json:"search"`
+	EmailProfile string   `json:"email_profile"`
+}
+
+// Report writes its files into dir.
+type Report func(definition *Definition, dir string) error
+
+type RunStatus string
+
+const (
+	SucceededRun RunStatus = "succeeded"
+	FailedRun    RunStatus = "failed"
+	// Another run of the same report still held the lock
+	SkippedRun RunStatus = "skipped"
+)
+
+type Run struct {
+	Name     string    `json:"name"`
+	Report   string    `json:"report"`
+	Started  string    `json:"started"`
+	Finished string    `json:"finished"`
+	Status   RunStatus `json:"status"`
+	Dir      string    `json:"dir"`
+	Files    []string  `json:"files"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// A running report touches its lock every lockHeartbeat, a lock untouched for staleLockAge is left
+// over from a crashed run and is taken over.
+const (
+	lockHeartbeat = time.Minute
+	staleLockAge  = 5 * time.Minute
+)
+
+type Scheduler struct {
+	Config  *Config
+	Reports map[string]Report
+	// Runs share one Canvas rate limit, so they are never run side by side within the process
+	mu sync.Mutex
+}
+
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, terror.Error(err, fmt.Sprintf("cannot read schedule config: %s", path))
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, terror.Error(err, "cannot unmarshal schedule config")
+	}
+
+	if config.OutputDir == "" {
+		config.OutputDir = "reports"
+	}
+
+	return config, nil
+}
+
+func NewScheduler(config *Config, reports map[string]Report) *Scheduler {
+	return &Scheduler{
+		Config:  config,
+		Reports: reports,
+	}
+}
+
+func (s *Scheduler) Definition(name string) (*Definition, error) {
+	for _, definition := range s.Config.Reports {
+		if definition.Name == name {
+			return definition, nil
+		}
+	}
+
+	return nil, terror.Error(fmt.Errorf("unknown report: %s", name), "report not found in schedule config")
+}
+
+// Start checks every definition and runs them on their schedule until stop is closed.
+func (s *Scheduler) Start(stop <-chan struct{}) error {
+	// A panicking report must not take the scheduler down with it
+	c := cron.New(cron.WithChain(cron.Recover(cron.DefaultLogger)))
+	for _, definition := range s.Config.Reports {
+		definition := definition
+		if s.Reports[definition.Report] == nil {
+			return terror.Error(fmt.Errorf("unknown report type: %s", definition.Report), fmt.Sprintf("cannot schedule %s", definition.Name))
+		}
+
+		_, err := c.AddFunc(definition.Cron, func() {
+			run := s.Run(definition)
+			log.Printf("%s %s in %s %s", run.Name, run.Status, run.Dir, run.Error)
+		})
+		if err != nil {
+			return terror.Error(err, fmt.Sprintf("invalid cron expression of %s: %s", definition.Name, definition.Cron))
+		}
+		log.Printf("scheduled %s (%s) at %s", definition.Name, definition.Report, definition.Cron)
+	}
+
+	c.Start()
+	<-stop
+	<-c.Stop().Done()
+
+	return nil
+}
+
+// Run runs a report now into a folder of its own and records how it went.
+// Runs of the same day sit side by side, so a run never picks up the files of an earlier one.
+func (s *Scheduler) Run(definition *Definition) *Run {
+	started := time.Now()
+	run := &Run{
+		Name:    definition.Name,
+		Report:  definition.Report,
+		Started: started.Format(time.RFC3339),
+		Dir:     filepath.Join(s.Config.OutputDir, started.Format("2006-01-02"), definition.Name, started.Format("15-04-05")),
+	}
+
+	unlock, err := s.lock(definition.Name)
+	if err != nil {
+		run.Status = SkippedRun
+		run.Error = err.Error()
+		return s.record(run)
+	}
+	defer unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := s.Reports[definition.Report]
+	if report == nil {
+		err = fmt.Errorf("unknown report type: %s", definition.Report)
+	} else if err = os.MkdirAll(run.Dir, 0o755); err == nil {
+		err = runReport(report, definition, run.Dir)
+	}
+
+	run.Status = SucceededRun
+	if err != nil {
+		run.Status = FailedRun
+		run.Error = err.Error()
+	}
+
+	entries, _ := os.ReadDir(run.Dir)
+	for _, entry := range entries {
+		run.Files = append(run.Files, entry.Name())
+	}
+
+	return s.record(run)
+}
+
+// runReport turns a panic of the report into a failed run.
+func runReport(report Report, definition *Definition, dir string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("report panicked: %v", r)
+		}
+	}()
+
+	return report(definition, dir)
+}
+
+// lock holds a lock file per report so a second scheduler or a manual run can't overlap with it.
+func (s *Scheduler) lock(name string) (func(), error) {
+	dir := filepath.Join(s.Config.OutputDir, ".locks")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, terror.Error(err, "cannot create lock folder")
+	}
+
+	path := filepath.Join(dir, name+".lock")
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+		os.Remove(path)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, terror.Error(fmt.Errorf("%s is already running: %w", name, err), "report is locked by another run")
+	}
+	file.WriteString(strconv.Itoa(os.Getpid()))
+	file.Close()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				os.Chtimes(path, now, now)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		os.Remove(path)
+	}, nil
+}
+
+func (s *Scheduler) record(run *Run) *Run {
+	run.Finished = time.Now().Format(time.RFC3339)
+
+	line, err := json.Marshal(run)
+	if err != nil {
+		log.Printf("cannot record run of %s: %s", run.Name, err)
+		return run
+	}
+
+	file, err := os.OpenFile(s.runsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("cannot record run of %s: %s", run.Name, err)
+		return run
+	}
+	defer file.Close()
+
+	file.Write(append(line, '\n'))
+
+	return run
+}
+
+func (s *Scheduler) runsPath() string {
+	return filepath.Join(s.Config.OutputDir, "runs.jsonl")
+}
+
+// Runs returns the recorded runs, oldest first.
+func (s *Scheduler) Runs() ([]*Run, error) {
+	content, err := os.ReadFile(s.runsPath())
+	if os.IsNotExist(err) {
+		return []*Run{}, nil
+	}
+	if err != nil {
+		return nil, terror.Error(err, "cannot read run status")
+	}
+
+	runs := []*Run{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	for decoder.More() {
+		run := &Run{}
+		if err := decoder.Decode(run); err != nil {
+			return nil, terror.Error(err, "cannot unmarshal run status")
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
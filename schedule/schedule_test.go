@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeReport(definition *Definition, dir string) error {
+	return os.WriteFile(filepath.Join(dir, definition.Name+".csv"), []byte("a,b\n"), 0o644)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		// Age of a lock file left by another run, none without
+		lockAge   time.Duration
+		want      RunStatus
+		wantFiles []string
+		wantError string
+	}{
+		{name: "succeeded", report: "write", want: SucceededRun, wantFiles: []string{"test.csv"}},
+		{name: "failed", report: "fail", want: FailedRun, wantError: "no data"},
+		{name: "panicked", report: "panic", want: FailedRun, wantError: "report panicked: boom"},
+		{name: "unknown report", report: "missing", want: FailedRun, wantError: "unknown report type: missing"},
+		{name: "locked by another run", report: "write", lockAge: time.Second, want: SkippedRun},
+		{name: "stale lock taken over", report: "write", lockAge: staleLockAge + time.Minute, want: SucceededRun, wantFiles: []string{"test.csv"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheduler := NewScheduler(&Config{OutputDir: t.TempDir()}, map[string]Report{
+				"write": writeReport,
+				"fail": func(definition *Definition, dir string) error {
+					return errors.New("no data")
+				},
+				"panic": func(definition *Definition, dir string) error {
+					panic("boom")
+				},
+			})
+			definition := &Definition{Name: "test", Report: test.report}
+
+			lockPath := filepath.Join(scheduler.Config.OutputDir, ".locks", "test.lock")
+			if test.lockAge > 0 {
+				os.MkdirAll(filepath.Dir(lockPath), 0o755)
+				os.WriteFile(lockPath, []byte("1"), 0o644)
+				modified := time.Now().Add(-test.lockAge)
+				os.Chtimes(lockPath, modified, modified)
+			}
+
+			run := scheduler.Run(definition)
+			if run.Status != test.want {
+				t.Fatalf("Run() status %s (%s), want %s", run.Status, run.Error, test.want)
+			}
+			if test.wantError != "" && run.Error != test.wantError {
+				t.Errorf("Run() error %q, want %q", run.Error, test.wantError)
+			}
+			if !reflect.DeepEqual(run.Files, test.wantFiles) {
+				t.Errorf("Run() files %v, want %v", run.Files, test.wantFiles)
+			}
+
+			if run.Status != SkippedRun {
+				started, _ := time.Parse(time.RFC3339, run.Started)
+				wantDir := filepath.Join(scheduler.Config.OutputDir, started.Format("2006-01-02"), "test", started.Format("15-04-05"))
+				if run.Dir != wantDir {
+					t.Errorf("Run() dir %s, want %s", run.Dir, wantDir)
+				}
+				if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+					t.Errorf("lock file left after the run")
+				}
+			}
+
+			runs, err := scheduler.Runs()
+			if err != nil {
+				t.Fatalf("Runs() error: %v", err)
+			}
+			if len(runs) != 1 || !reflect.DeepEqual(runs[0], run) {
+				t.Errorf("Runs() %+v, want the run %+v", runs, run)
+			}
+		})
+	}
+}
+
+func TestRunOverlap(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	scheduler := NewScheduler(&Config{OutputDir: t.TempDir()}, map[string]Report{
+		"slow": func(definition *Definition, dir string) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+	definition := &Definition{Name: "test", Report: "slow"}
+
+	first := make(chan *Run)
+	go func() {
+		first <- scheduler.Run(definition)
+	}()
+	<-started
+
+	second := scheduler.Run(definition)
+	close(release)
+
+	if second.Status != SkippedRun {
+		t.Errorf("second Run() status %s, want %s", second.Status, SkippedRun)
+	}
+	if run := <-first; run.Status != SucceededRun {
+		t.Errorf("first Run() status %s (%s), want %s", run.Status, run.Error, SucceededRun)
+	}
+
+	runs, err := scheduler.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error: %v", err)
+	}
+	statuses := []RunStatus{}
+	for _, run := range runs {
+		statuses = append(statuses, run.Status)
+	}
+	if !reflect.DeepEqual(statuses, []RunStatus{SkippedRun, SucceededRun}) {
+		t.Errorf("Runs() statuses %v, want the skipped run then the finished one", statuses)
+	}
+}